@@ -3,6 +3,7 @@ package flutterproject
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
@@ -17,29 +18,64 @@ type SDKVersionFinder interface {
 	FindLatestReleaseFor(platform fluttersdk.Platform, architecture fluttersdk.Architecture, channel fluttersdk.Channel, query fluttersdk.SDKQuery) (*fluttersdk.Release, error)
 }
 
-type FlutterAndDartSDKVersions struct {
-	FVMFlutterVersion         *semver.Version
-	FVMFlutterChannel         string
-	ASDFFlutterVersion        *semver.Version
-	ASDFFlutterChannel        string
-	PubspecFlutterVersion     *sdk.VersionConstraint
-	PubspecDartVersion        *sdk.VersionConstraint
-	PubspecLockFlutterVersion *sdk.VersionConstraint
-	PubspecLockDartVersion    *sdk.VersionConstraint
+/*
+SDKReleaseLookup is implemented by SDK version finders, which can look up a release by its commit or its channel's current release,
+like fluttersdk.SDKVersionFinder.
+
+Commit pins and the .metadata revision are only resolved if the project's SDKVersionFinder implements it,
+without it channel head pins resolve to the latest release of the channel.
+*/
+type SDKReleaseLookup interface {
+	FindReleaseByHash(platform fluttersdk.Platform, architecture fluttersdk.Architecture, hash string) (*fluttersdk.Release, error)
+	FindChannelHead(platform fluttersdk.Platform, architecture fluttersdk.Architecture, channel fluttersdk.Channel) (*fluttersdk.Release, error)
 }
 
-type Pubspec struct {
-	Name string `yaml:"name"`
+type FlutterAndDartSDKVersions struct {
+	FVMFlutterVersion            *semver.Version
+	FVMFlutterChannel            string
+	FVMFlutterCommit             string
+	FVMConfigFile                string
+	ASDFFlutterVersion           *semver.Version
+	ASDFFlutterChannel           string
+	ASDFFlutterCommit            string
+	ASDFConfigFile               string
+	MiseFlutterVersion           *sdk.VersionConstraint
+	MiseFlutterChannel           string
+	MiseConfigFile               string
+	PuroFlutterVersion           *semver.Version
+	PuroFlutterChannel           string
+	ProtoFlutterVersion          *sdk.VersionConstraint
+	ProtoFlutterChannel          string
+	PubspecFlutterVersion        *sdk.VersionConstraint
+	PubspecDartVersion           *sdk.VersionConstraint
+	PubspecLockFlutterVersion    *sdk.VersionConstraint
+	PubspecLockDartVersion       *sdk.VersionConstraint
+	PathDependencyFlutterVersion *sdk.VersionConstraint
+	PathDependencyDartVersion    *sdk.VersionConstraint
+
+	GitHubActionsFlutterVersion *sdk.VersionConstraint
+	GitHubActionsFlutterChannel string
+	GitHubActionsWorkflowFile   string
+	CodemagicFlutterVersion     *sdk.VersionConstraint
+	CodemagicFlutterChannel     string
+	BitriseFlutterVersion       *sdk.VersionConstraint
+	BitriseFlutterChannel       string
 }
 
 type Project struct {
 	rootDir    string
 	pubspecPth string
-	pubspec    Pubspec
+	pubspec    PubspecInfo
 
 	fileManager      fileutil.FileManager
 	pathChecker      pathutil.PathChecker
 	sdkVersionFinder SDKVersionFinder
+
+	versionSources *VersionSourceRegistry
+
+	resolutionStrategy       fluttersdk.ResolutionStrategy
+	installedFlutterVersions []*semver.Version
+	pinRelease               bool
 }
 
 func New(rootDir string, fileManager fileutil.FileManager, pathChecker pathutil.PathChecker, sdkVersionFinder SDKVersionFinder) (*Project, error) {
@@ -49,7 +85,9 @@ func New(rootDir string, fileManager fileutil.FileManager, pathChecker pathutil.
 		return nil, fmt.Errorf("failed to open %s: %s", pubspecPth, err)
 	}
 
-	var pubspec Pubspec
+	var pubspec struct {
+		Name string `yaml:"name"`
+	}
 	if err := yaml.NewDecoder(pubspecFile).Decode(&pubspec); err != nil {
 		return nil, fmt.Errorf("failed to parse pubspec.yaml at %s: %s", pubspecPth, err)
 	}
@@ -57,7 +95,7 @@ func New(rootDir string, fileManager fileutil.FileManager, pathChecker pathutil.
 	return &Project{
 		rootDir:          rootDir,
 		pubspecPth:       pubspecPth,
-		pubspec:          pubspec,
+		pubspec:          PubspecInfo{Name: pubspec.Name},
 		fileManager:      fileManager,
 		pathChecker:      pathChecker,
 		sdkVersionFinder: sdkVersionFinder,
@@ -68,10 +106,61 @@ func (p *Project) RootDir() string {
 	return p.rootDir
 }
 
-func (p *Project) Pubspec() Pubspec {
+// PubspecInfo is the part of pubspec.yaml read by New.
+type PubspecInfo struct {
+	Name string
+}
+
+// Pubspec returns the part of pubspec.yaml read by New, PubspecModel parses the whole pubspec.yaml.
+func (p *Project) Pubspec() PubspecInfo {
 	return p.pubspec
 }
 
+// PubspecModel parses the project's pubspec.yaml, unlike New it fails on an invalid dependency or flutter section.
+func (p *Project) PubspecModel() (*Pubspec, error) {
+	f, err := p.fileManager.Open(p.pubspecPth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %s", p.pubspecPth, err)
+	}
+	defer f.Close()
+
+	pubspec, err := ParsePubspec(f, p.pubspecPth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pubspec.yaml at %s: %w", p.pubspecPth, err)
+	}
+	return &pubspec, nil
+}
+
+// WithFVMFlavor returns a copy of the project, which resolves the Flutter SDK version pinned for the given FVM flavor.
+func (p *Project) WithFVMFlavor(flavor string) *Project {
+	sources := p.VersionSources().Sources()
+	for i, source := range sources {
+		if _, ok := source.(fvmVersionSource); ok {
+			sources[i] = fvmVersionSource{flavor: flavor}
+		}
+	}
+
+	project := *p
+	project.versionSources = NewVersionSourceRegistry(sources...)
+	return &project
+}
+
+// FVMFlavors returns the names of the FVM flavors defined in the project's FVM config.
+func (p *Project) FVMFlavors() ([]string, error) {
+	flavors, _, err := sdk.NewFVMVersionReader(p.fileManager).ReadFlavors(p.rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range flavors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
 func (p *Project) TestDirPth() string {
 	const testDirRelPth = "test"
 
@@ -137,102 +226,285 @@ func (p *Project) AndroidProjectPth() string {
 	return androidProjectPth
 }
 
+// VersionSources returns the project's SDK version source registry, which can be used to add, reorder or remove sources.
+func (p *Project) VersionSources() *VersionSourceRegistry {
+	if p.versionSources == nil {
+		p.versionSources = NewVersionSourceRegistry(DefaultVersionSources()...)
+	}
+	return p.versionSources
+}
+
+func (p *Project) readVersionSources() ([]VersionSourceResult, error) {
+	return p.VersionSources().Read(VersionSourceContext{
+		RootDir:    p.rootDir,
+		FileOpener: p.fileManager,
+		DirReader:  p.fileManager,
+	})
+}
+
 func (p *Project) FlutterAndDartSDKVersions() (FlutterAndDartSDKVersions, error) {
+	results, err := p.readVersionSources()
+	if err != nil {
+		return FlutterAndDartSDKVersions{}, err
+	}
+
+	return newFlutterAndDartSDKVersions(results), nil
+}
+
+// newFlutterAndDartSDKVersions collects the results of the built-in and CI config SDK version sources.
+func newFlutterAndDartSDKVersions(results []VersionSourceResult) FlutterAndDartSDKVersions {
 	sdkVersions := FlutterAndDartSDKVersions{}
 
-	fvmFlutterVersion, fvmFlutterChannel, err := sdk.NewFVMVersionReader(p.fileManager).ReadSDKVersion(p.rootDir)
+	for _, result := range results {
+		switch result.Source {
+		case FVMVersionSourceName:
+			sdkVersions.FVMFlutterVersion = result.FlutterVersion
+			sdkVersions.FVMFlutterChannel = result.FlutterChannel
+			sdkVersions.FVMFlutterCommit = result.FlutterCommit
+			if result.FlutterVersion != nil || result.FlutterChannel != "" || result.FlutterCommit != "" {
+				sdkVersions.FVMConfigFile = result.File
+			}
+		case ASDFVersionSourceName:
+			sdkVersions.ASDFFlutterVersion = result.FlutterVersion
+			sdkVersions.ASDFFlutterChannel = result.FlutterChannel
+			sdkVersions.ASDFFlutterCommit = result.FlutterCommit
+			if result.FlutterVersion != nil || result.FlutterChannel != "" || result.FlutterCommit != "" {
+				sdkVersions.ASDFConfigFile = result.File
+			}
+		case MiseVersionSourceName:
+			sdkVersions.MiseFlutterVersion = newVersionConstraint(result.FlutterVersion, result.FlutterVersionConstraint)
+			sdkVersions.MiseFlutterChannel = result.FlutterChannel
+			if sdkVersions.MiseFlutterVersion != nil || result.FlutterChannel != "" {
+				sdkVersions.MiseConfigFile = result.File
+			}
+		case PuroVersionSourceName:
+			sdkVersions.PuroFlutterVersion = result.FlutterVersion
+			sdkVersions.PuroFlutterChannel = result.FlutterChannel
+		case ProtoVersionSourceName:
+			sdkVersions.ProtoFlutterVersion = newVersionConstraint(result.FlutterVersion, result.FlutterVersionConstraint)
+			sdkVersions.ProtoFlutterChannel = result.FlutterChannel
+		case PubspecLockVersionSourceName:
+			sdkVersions.PubspecLockFlutterVersion = newVersionConstraint(result.FlutterVersion, result.FlutterVersionConstraint)
+			sdkVersions.PubspecLockDartVersion = newVersionConstraint(result.DartVersion, result.DartVersionConstraint)
+		case PubspecVersionSourceName:
+			sdkVersions.PubspecFlutterVersion = newVersionConstraint(result.FlutterVersion, result.FlutterVersionConstraint)
+			sdkVersions.PubspecDartVersion = newVersionConstraint(result.DartVersion, result.DartVersionConstraint)
+		case PathDependencyVersionSourceName:
+			sdkVersions.PathDependencyFlutterVersion = newVersionConstraint(result.FlutterVersion, result.FlutterVersionConstraint)
+			sdkVersions.PathDependencyDartVersion = newVersionConstraint(result.DartVersion, result.DartVersionConstraint)
+		case GitHubActionsVersionSourceName:
+			sdkVersions.GitHubActionsFlutterVersion = newVersionConstraint(result.FlutterVersion, result.FlutterVersionConstraint)
+			sdkVersions.GitHubActionsFlutterChannel = result.FlutterChannel
+			if sdkVersions.GitHubActionsFlutterVersion != nil || result.FlutterChannel != "" {
+				sdkVersions.GitHubActionsWorkflowFile = result.File
+			}
+		case CodemagicVersionSourceName:
+			sdkVersions.CodemagicFlutterVersion = newVersionConstraint(result.FlutterVersion, result.FlutterVersionConstraint)
+			sdkVersions.CodemagicFlutterChannel = result.FlutterChannel
+		case BitriseVersionSourceName:
+			sdkVersions.BitriseFlutterVersion = newVersionConstraint(result.FlutterVersion, result.FlutterVersionConstraint)
+			sdkVersions.BitriseFlutterChannel = result.FlutterChannel
+		}
+	}
+
+	return sdkVersions
+}
+
+// newVersionConstraint converts a built-in source's requirement, which are either exact versions or pub constraints.
+func newVersionConstraint(version *semver.Version, constraint fluttersdk.VersionConstraint) *sdk.VersionConstraint {
+	pubConstraint, _ := constraint.(*sdk.Constraint)
+	if version == nil && pubConstraint == nil {
+		return nil
+	}
+	return &sdk.VersionConstraint{Version: version, Constraint: pubConstraint}
+}
+
+// FlutterSDKVersionToUse returns the version and channel of the Flutter SDK release to use on the host machine,
+// hosts without Flutter SDK releases (like linux/386) resolve against the macOS arm64 releases, as before the host was detected.
+// Returns a fluttersdk.NoMatchingReleaseError if no release matches the project's SDK requirements.
+func (p *Project) FlutterSDKVersionToUse() (string, string, error) {
+	platform, architecture, err := fluttersdk.HostPlatformAndArchitecture()
 	if err != nil {
-		return FlutterAndDartSDKVersions{}, err
-	} else {
-		sdkVersions.FVMFlutterVersion = fvmFlutterVersion
-		sdkVersions.FVMFlutterChannel = fvmFlutterChannel
+		platform, architecture = fluttersdk.MacOS, fluttersdk.ARM64
 	}
 
-	asdfFlutterVersion, asdfFlutterChannel, err := sdk.NewASDFVersionReader(p.fileManager).ReadSDKVersions(p.rootDir)
+	release, err := p.FlutterSDKReleaseToUse(platform, architecture)
 	if err != nil {
-		return FlutterAndDartSDKVersions{}, err
-	} else {
-		sdkVersions.ASDFFlutterVersion = asdfFlutterVersion
-		sdkVersions.ASDFFlutterChannel = asdfFlutterChannel
+		return "", "", err
 	}
 
-	pubspecLockFlutterVersion, pubspecLockDartVersion, err := sdk.NewPubspecLockVersionReader(p.fileManager).ReadSDKVersions(p.rootDir)
+	return release.Version, release.Channel, nil
+}
+
+/*
+FlutterSDKReleaseToUse returns the Flutter SDK release, matching the project's SDK requirements, for the given platform and architecture.
+
+Commit pins resolve to the release built from the commit, channel head pins to the channel's current release.
+If no source pins or constrains the Flutter SDK, the release the project was created or last migrated with (the .metadata source)
+is preferred over the latest release, as long as it satisfies the project's Dart SDK requirements.
+Version constraints are resolved with the project's resolution strategy (see WithResolutionStrategy),
+the .metadata revision is only preferred with the default strategy.
+Projects created with WithReleasePin reuse the release recorded at the first resolution.
+Returns an SDKRequirementConflictError if the sources' requirements have no version in common,
+and a fluttersdk.NoMatchingReleaseError if no release matches the requirements.
+*/
+func (p *Project) FlutterSDKReleaseToUse(platform fluttersdk.Platform, architecture fluttersdk.Architecture) (*fluttersdk.Release, error) {
+	if err := p.resolutionStrategy.Validate(); err != nil {
+		return nil, err
+	}
+
+	resolution, err := p.ResolveSDKVersions()
 	if err != nil {
-		return FlutterAndDartSDKVersions{}, err
-	} else {
-		sdkVersions.PubspecLockFlutterVersion = pubspecLockFlutterVersion
-		sdkVersions.PubspecLockDartVersion = pubspecLockDartVersion
+		return nil, err
 	}
 
-	pubspecFlutterVersion, pubspecDartVersion, err := sdk.NewPubspecVersionReader(p.fileManager).ReadSDKVersions(p.rootDir)
+	if !p.pinRelease {
+		return p.releaseFor(platform, architecture, *resolution)
+	}
+
+	release, err := p.pinnedReleaseFor(platform, architecture, *resolution)
 	if err != nil {
-		return FlutterAndDartSDKVersions{}, err
-	} else {
-		sdkVersions.PubspecFlutterVersion = pubspecFlutterVersion
-		sdkVersions.PubspecDartVersion = pubspecDartVersion
+		return nil, err
+	}
+	if release != nil {
+		return release, nil
 	}
 
-	return sdkVersions, nil
+	release, err = p.releaseFor(platform, architecture, *resolution)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.writeReleasePin(*release); err != nil {
+		return nil, err
+	}
+	return release, nil
 }
 
-func (p *Project) FlutterSDKVersionToUse() (string, string, error) {
-	sdkVersions, err := p.FlutterAndDartSDKVersions()
+func (p *Project) releaseFor(platform fluttersdk.Platform, architecture fluttersdk.Architecture, resolution SDKResolution) (*fluttersdk.Release, error) {
+	if len(resolution.Conflicts) > 0 {
+		return nil, resolution.Conflicts[0]
+	}
+
+	lookup, hasLookup := p.sdkVersionFinder.(SDKReleaseLookup)
+	switch resolution.FlutterRequirement {
+	case FlutterRequirementCommit:
+		if !hasLookup {
+			return nil, fmt.Errorf("the SDK version finder can't look up releases by commit: %s", resolution.FlutterCommit)
+		}
+		release, err := lookup.FindReleaseByHash(platform, architecture, resolution.FlutterCommit)
+		if err != nil {
+			return nil, err
+		}
+		return pinnedRelease(platform, architecture, release, resolution)
+	case FlutterRequirementChannelHead:
+		if !hasLookup {
+			break
+		}
+		release, err := lookup.FindChannelHead(platform, architecture, fluttersdk.Channel(resolution.FlutterChannel))
+		if err != nil {
+			return nil, err
+		}
+		return pinnedRelease(platform, architecture, release, resolution)
+	case FlutterRequirementPreferredCommit:
+		if !hasLookup || (p.resolutionStrategy != "" && p.resolutionStrategy != fluttersdk.Newest) {
+			break
+		}
+		release, err := lookup.FindReleaseByHash(platform, architecture, resolution.FlutterCommit)
+		if err != nil {
+			return nil, err
+		}
+		release, err = matchingRelease(release, resolution)
+		if err != nil {
+			return nil, err
+		}
+		if release != nil {
+			return release, nil
+		}
+	}
+
+	release, err := p.sdkVersionFinder.FindLatestReleaseFor(platform, architecture, fluttersdk.Channel(resolution.FlutterChannel), p.strategyQuery(resolution))
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+	// finders, which don't return a NoMatchingReleaseError, return a nil release if nothing matches
+	if release == nil {
+		return nil, noMatchingReleaseError(platform, architecture, resolution)
 	}
+	return release, nil
+}
+
+// strategyQuery returns the resolved query with the project's resolution strategy,
+// the strategy only applies to version ranges, without requirements the latest release is used.
+func (p *Project) strategyQuery(resolution SDKResolution) fluttersdk.SDKQuery {
+	query := resolution.Query
+	if resolution.FlutterRequirement == FlutterRequirementConstraint || query.DartVersion != nil || query.DartVersionConstraint != nil {
+		query.Strategy = p.resolutionStrategy
+		query.InstalledFlutterVersions = p.installedFlutterVersions
+	}
+	return query
+}
 
-	sdkQuery, flutterChannel := createSDKQuery(sdkVersions)
-	release, err := p.sdkVersionFinder.FindLatestReleaseFor(fluttersdk.MacOS, fluttersdk.ARM64, fluttersdk.Channel(flutterChannel), sdkQuery)
+// MetadataRevision returns the Flutter framework revision and channel stored in the project's .metadata file.
+func (p *Project) MetadataRevision() (string, string, error) {
+	return sdk.NewMetadataReader(p.fileManager).ReadRevision(p.rootDir)
+}
+
+// pinnedRelease returns the release resolved for a commit or a channel head pin,
+// or a NoMatchingReleaseError if it doesn't exist or doesn't satisfy the rest of the requirements.
+func pinnedRelease(platform fluttersdk.Platform, architecture fluttersdk.Architecture, release *fluttersdk.Release, resolution SDKResolution) (*fluttersdk.Release, error) {
+	release, err := matchingRelease(release, resolution)
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+	if release != nil {
+		return release, nil
+	}
+	return nil, noMatchingReleaseError(platform, architecture, resolution)
+}
+
+func noMatchingReleaseError(platform fluttersdk.Platform, architecture fluttersdk.Architecture, resolution SDKResolution) fluttersdk.NoMatchingReleaseError {
+	channels := []fluttersdk.Channel{fluttersdk.Stable, fluttersdk.Beta, fluttersdk.Dev}
+	if resolution.FlutterChannel != "" {
+		channels = []fluttersdk.Channel{fluttersdk.Channel(resolution.FlutterChannel)}
+	}
+
+	err := fluttersdk.NoMatchingReleaseError{
+		Platform:     platform,
+		Architecture: architecture,
+		Channels:     channels,
+		Query:        resolution.Query,
+		ChannelHead:  resolution.FlutterRequirement == FlutterRequirementChannelHead,
+	}
+	if resolution.FlutterRequirement == FlutterRequirementCommit {
+		err.Commit = resolution.FlutterCommit
 	}
+	return err
+}
+
+// matchingRelease returns the release if it is on the resolved channel and satisfies the resolved version requirements.
+func matchingRelease(release *fluttersdk.Release, resolution SDKResolution) (*fluttersdk.Release, error) {
 	if release == nil {
-		return "", "", nil
+		return nil, nil
 	}
 
-	return release.Version, release.Channel, nil
+	if resolution.FlutterChannel != "" && resolution.FlutterChannel != release.Channel {
+		return nil, nil
+	}
+	if match, err := resolution.Query.Matches(*release); err != nil || !match {
+		return nil, err
+	}
+
+	return release, nil
 }
 
-func createSDKQuery(sdkVersions FlutterAndDartSDKVersions) (fluttersdk.SDKQuery, string) {
-	var flutterVersion *semver.Version
-	var flutterVersionConstraint *semver.Constraints
-	flutterChannel := ""
-
-	switch {
-	case sdkVersions.FVMFlutterVersion != nil:
-		flutterVersion = sdkVersions.FVMFlutterVersion
-		flutterChannel = sdkVersions.FVMFlutterChannel
-	case sdkVersions.ASDFFlutterVersion != nil:
-		flutterVersion = sdkVersions.ASDFFlutterVersion
-		flutterChannel = sdkVersions.ASDFFlutterChannel
-	case sdkVersions.PubspecLockFlutterVersion != nil && sdkVersions.PubspecLockFlutterVersion.Version != nil:
-		flutterVersion = sdkVersions.PubspecLockFlutterVersion.Version
-	case sdkVersions.PubspecLockFlutterVersion != nil && sdkVersions.PubspecLockFlutterVersion.Constraint != nil:
-		flutterVersionConstraint = sdkVersions.PubspecLockFlutterVersion.Constraint
-	case sdkVersions.PubspecFlutterVersion != nil && sdkVersions.PubspecFlutterVersion.Version != nil:
-		flutterVersion = sdkVersions.PubspecFlutterVersion.Version
-	case sdkVersions.PubspecFlutterVersion != nil && sdkVersions.PubspecFlutterVersion.Constraint != nil:
-		flutterVersionConstraint = sdkVersions.PubspecFlutterVersion.Constraint
-	}
-
-	var dartVersion *semver.Version
-	var dartVersionConstraint *semver.Constraints
-
-	switch {
-	case sdkVersions.PubspecLockDartVersion != nil && sdkVersions.PubspecLockDartVersion.Version != nil:
-		dartVersion = sdkVersions.PubspecLockDartVersion.Version
-	case sdkVersions.PubspecLockDartVersion != nil && sdkVersions.PubspecLockDartVersion.Constraint != nil:
-		dartVersionConstraint = sdkVersions.PubspecLockDartVersion.Constraint
-	case sdkVersions.PubspecDartVersion != nil && sdkVersions.PubspecDartVersion.Version != nil:
-		dartVersion = sdkVersions.PubspecDartVersion.Version
-	case sdkVersions.PubspecDartVersion != nil && sdkVersions.PubspecDartVersion.Constraint != nil:
-		dartVersionConstraint = sdkVersions.PubspecDartVersion.Constraint
-	}
-
-	return fluttersdk.SDKQuery{
-		FlutterVersion:           flutterVersion,
-		FlutterVersionConstraint: flutterVersionConstraint,
-		DartVersion:              dartVersion,
-		DartVersionConstraint:    dartVersionConstraint,
-	}, flutterChannel
+// ResolveSDKVersions picks the Flutter and Dart SDK requirements from the project's SDK version sources
+// and explains which source was selected or ignored and why.
+func (p *Project) ResolveSDKVersions() (*SDKResolution, error) {
+	results, err := p.readVersionSources()
+	if err != nil {
+		return nil, err
+	}
+
+	resolution := createSDKQuery(results)
+	return &resolution, nil
 }
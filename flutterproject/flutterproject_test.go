@@ -2,9 +2,11 @@ package flutterproject
 
 import (
 	"encoding/json"
+	"io"
 	"strings"
 	"testing"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/bitrise-io/go-flutter/flutterproject/internal/testassets"
 	"github.com/bitrise-io/go-flutter/fluttersdk"
 	"github.com/bitrise-io/go-flutter/mocks"
@@ -18,6 +20,7 @@ func TestProject_FlutterAndDartSDKVersions(t *testing.T) {
 	fileOpener.On("OpenReaderIfExists", ".tool-versions").Return(strings.NewReader(testassets.ToolVersions), nil)
 	fileOpener.On("OpenReaderIfExists", "pubspec.lock").Return(strings.NewReader(testassets.PubspecLock), nil)
 	fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(strings.NewReader(testassets.PubspecYaml), nil)
+	fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
 
 	proj := Project{
 		fileManager: fileOpener,
@@ -32,8 +35,19 @@ func TestProject_FlutterAndDartSDKVersions(t *testing.T) {
 	require.Equal(t, string(b), `{
 	"FVMFlutterVersion": "3.7.12",
 	"FVMFlutterChannel": "",
+	"FVMFlutterCommit": "",
+	"FVMConfigFile": ".fvm/fvm_config.json",
 	"ASDFFlutterVersion": "3.7.12",
 	"ASDFFlutterChannel": "",
+	"ASDFFlutterCommit": "",
+	"ASDFConfigFile": ".tool-versions",
+	"MiseFlutterVersion": null,
+	"MiseFlutterChannel": "",
+	"MiseConfigFile": "",
+	"PuroFlutterVersion": null,
+	"PuroFlutterChannel": "",
+	"ProtoFlutterVersion": null,
+	"ProtoFlutterChannel": "",
 	"PubspecFlutterVersion": {
 		"Version": null,
 		"Constraint": "^3.7.12"
@@ -46,7 +60,16 @@ func TestProject_FlutterAndDartSDKVersions(t *testing.T) {
 	"PubspecLockDartVersion": {
 		"Version": null,
 		"Constraint": "\u003e=2.19.6 \u003c3.0.0"
-	}
+	},
+	"PathDependencyFlutterVersion": null,
+	"PathDependencyDartVersion": null,
+	"GitHubActionsFlutterVersion": null,
+	"GitHubActionsFlutterChannel": "",
+	"GitHubActionsWorkflowFile": "",
+	"CodemagicFlutterVersion": null,
+	"CodemagicFlutterChannel": "",
+	"BitriseFlutterVersion": null,
+	"BitriseFlutterChannel": ""
 }`)
 }
 
@@ -57,6 +80,7 @@ func TestProject_FlutterSDKVersionToUse(t *testing.T) {
 		projectSDKFromToolVersions string
 		wantVersion                string
 		wantChannel                string
+		wantErr                    string
 	}{
 		{
 			name: "Project required version is available",
@@ -77,14 +101,13 @@ func TestProject_FlutterSDKVersionToUse(t *testing.T) {
 				DartSdkVersion: "3.1.4",
 			}}},
 			projectSDKFromToolVersions: "3.13.9",
-			wantVersion:                "",
-			wantChannel:                "",
+			wantErr:                    "no Flutter SDK release matches Flutter 3.13.9 on",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			availableSDKLister := new(mocks.SDKVersionLister)
-			availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything).Return(tt.availableSDKsByChannel, nil)
+			availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything, mock.Anything).Return(tt.availableSDKsByChannel, nil)
 
 			sdkVersionFinder := fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister}
 
@@ -93,15 +116,454 @@ func TestProject_FlutterSDKVersionToUse(t *testing.T) {
 			fileOpener.On("OpenReaderIfExists", ".fvm/fvm_config.json").Return(nil, nil)
 			fileOpener.On("OpenReaderIfExists", "pubspec.lock").Return(nil, nil)
 			fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(nil, nil)
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
 
 			p := &Project{
 				fileManager:      fileOpener,
 				sdkVersionFinder: sdkVersionFinder,
 			}
 			gotVersion, gotChannel, err := p.FlutterSDKVersionToUse()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				var noMatchingReleaseErr fluttersdk.NoMatchingReleaseError
+				require.ErrorAs(t, err, &noMatchingReleaseErr)
+				require.Equal(t, "3.13.8", noMatchingReleaseErr.Below.Version)
+				require.Nil(t, noMatchingReleaseErr.Above)
+				return
+			}
 			require.NoError(t, err)
 			require.Equal(t, tt.wantVersion, gotVersion)
 			require.Equal(t, tt.wantChannel, gotChannel)
 		})
 	}
 }
+
+func TestProject_FlutterSDKReleaseToUse(t *testing.T) {
+	availableSDKLister := new(mocks.SDKVersionLister)
+	availableSDKLister.On("ListReleasesByChannel", mock.Anything, fluttersdk.Linux, fluttersdk.X64).Return(map[string][]fluttersdk.Release{"stable": {{
+		Channel:        "stable",
+		Version:        "3.13.8",
+		DartSdkVersion: "3.1.4",
+		Archive:        "stable/linux/flutter_linux_3.13.8-stable.tar.xz",
+	}}}, nil)
+
+	fileOpener := new(mocks.FileManager)
+	fileOpener.On("OpenReaderIfExists", ".tool-versions").Return(strings.NewReader("flutter 3.13.8"), nil)
+	fileOpener.On("OpenReaderIfExists", ".fvm/fvm_config.json").Return(nil, nil)
+	fileOpener.On("OpenReaderIfExists", "pubspec.lock").Return(nil, nil)
+	fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(nil, nil)
+	fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+	p := &Project{
+		fileManager:      fileOpener,
+		sdkVersionFinder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister},
+	}
+	release, err := p.FlutterSDKReleaseToUse(fluttersdk.Linux, fluttersdk.X64)
+	require.NoError(t, err)
+	require.Equal(t, "3.13.8", release.Version)
+	require.Equal(t, "stable/linux/flutter_linux_3.13.8-stable.tar.xz", release.Archive)
+	availableSDKLister.AssertExpectations(t)
+}
+
+func TestProject_FlutterSDKReleaseToUse_MetadataRevision(t *testing.T) {
+	metadata := `version:
+  revision: "2f708eb8396e362e280fac22cf171c2cb467343c"
+  channel: "stable"
+
+project_type: app
+`
+	tests := []struct {
+		name        string
+		pubspec     string
+		toolVersion string
+		wantVersion string
+	}{
+		{
+			name:        "No requirements, uses the release the project was migrated with",
+			wantVersion: "3.13.8",
+		},
+		{
+			name:        "Migrated release satisfies the Dart constraint",
+			pubspec:     "environment:\n  sdk: \">=3.1.0\"",
+			wantVersion: "3.13.8",
+		},
+		{
+			name:        "Migrated release doesn't satisfy the Dart constraint",
+			pubspec:     "environment:\n  sdk: \">=3.1.5\"",
+			wantVersion: "3.13.9",
+		},
+		{
+			name:        "Flutter constraint takes precedence",
+			pubspec:     "environment:\n  flutter: \">=3.13.0\"",
+			wantVersion: "3.13.9",
+		},
+		{
+			name:        "Pinned version takes precedence",
+			toolVersion: "flutter 3.13.7",
+			wantVersion: "3.13.7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availableSDKLister := new(mocks.SDKVersionLister)
+			availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]fluttersdk.Release{"stable": {
+				{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5", Hash: "d211f42860350d914a5ad8102f9ec32764dc6d06"},
+				{Channel: "stable", Version: "3.13.8", DartSdkVersion: "3.1.4", Hash: "2f708eb8396e362e280fac22cf171c2cb467343c"},
+				{Channel: "stable", Version: "3.13.7", DartSdkVersion: "3.1.3", Hash: "2f708eb8396e362e280fac22cf171c2cb467343d"},
+			}}, nil)
+
+			fileOpener := new(mocks.FileManager)
+			fileOpener.On("OpenReaderIfExists", ".metadata").Return(strings.NewReader(metadata), nil)
+			if tt.toolVersion != "" {
+				fileOpener.On("OpenReaderIfExists", ".tool-versions").Return(strings.NewReader(tt.toolVersion), nil)
+			}
+			if tt.pubspec != "" {
+				fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(strings.NewReader(tt.pubspec), nil)
+			}
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			p := &Project{
+				fileManager:      fileOpener,
+				sdkVersionFinder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister},
+			}
+			release, err := p.FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantVersion, release.Version)
+		})
+	}
+}
+
+func TestProject_FlutterSDKReleaseToUse_ResolutionStrategy(t *testing.T) {
+	metadata := `version:
+  revision: "d211f42860350d914a5ad8102f9ec32764dc6d06"
+  channel: "stable"
+`
+	tests := []struct {
+		name        string
+		strategy    fluttersdk.ResolutionStrategy
+		installed   []*semver.Version
+		wantVersion string
+		wantErr     string
+	}{
+		{
+			name:        "Default strategy uses the latest release, the migrated release is not preferred over a constraint",
+			wantVersion: "3.16.0",
+		},
+		{
+			name:        "Oldest compatible",
+			strategy:    fluttersdk.OldestCompatible,
+			wantVersion: "3.10.5",
+		},
+		{
+			name:        "Newest patch of the lowest minor",
+			strategy:    fluttersdk.NewestPatchOfLowestMinor,
+			wantVersion: "3.10.6",
+		},
+		{
+			name:        "Prefer installed",
+			strategy:    fluttersdk.PreferInstalled,
+			installed:   []*semver.Version{semver.MustParse("3.13.8")},
+			wantVersion: "3.13.8",
+		},
+		{
+			name:     "Unknown strategy",
+			strategy: "oldest",
+			wantErr:  "unknown resolution strategy: oldest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availableSDKLister := new(mocks.SDKVersionLister)
+			availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]fluttersdk.Release{"stable": {
+				{Channel: "stable", Version: "3.16.0", DartSdkVersion: "3.2.0", Hash: "db7ef5bf9f59442b0e200a90587e8fa5e0c6336a"},
+				{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5", Hash: "d211f42860350d914a5ad8102f9ec32764dc6d06"},
+				{Channel: "stable", Version: "3.13.8", DartSdkVersion: "3.1.4", Hash: "6c4930c4ac86fb286f30e31d0ec8bffbcbb9953e"},
+				{Channel: "stable", Version: "3.10.6", DartSdkVersion: "3.0.6", Hash: "f468f3366c26a5092eb964a230ce7892fda8f2f8"},
+				{Channel: "stable", Version: "3.10.5", DartSdkVersion: "3.0.5", Hash: "796c8ef79279f9c774545b3771238c3098dbefab"},
+				{Channel: "stable", Version: "3.7.12", DartSdkVersion: "2.19.6", Hash: "4d9e56e694b656610ab87fcf2efbcd226e0ed8cf"},
+			}}, nil)
+
+			fileOpener := new(mocks.FileManager)
+			fileOpener.On("OpenReaderIfExists", ".metadata").Return(strings.NewReader(metadata), nil)
+			fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(strings.NewReader("environment:\n  flutter: \">=3.10.0\""), nil)
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			p := &Project{
+				fileManager:      fileOpener,
+				sdkVersionFinder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister},
+			}
+			release, err := p.WithResolutionStrategy(tt.strategy, tt.installed...).FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantVersion, release.Version)
+		})
+	}
+}
+
+func TestProject_FlutterSDKReleaseToUse_CombinedRequirements(t *testing.T) {
+	pubspec := `name: app
+environment:
+  sdk: ">=3.0.0 <4.0.0"
+  flutter: ">=3.10.0"
+dependencies:
+  core:
+    path: packages/core
+`
+	tests := []struct {
+		name                  string
+		pubspecLock           string
+		corePubspec           string
+		wantVersion           string
+		wantDartConstraint    string
+		wantFlutterConstraint string
+		wantErr               string
+	}{
+		{
+			name:                  "Bundled Dart satisfies every source",
+			pubspecLock:           "sdks:\n  dart: \">=3.1.0 <4.0.0\"\n",
+			corePubspec:           "name: core\nenvironment:\n  sdk: \"<3.2.0\"\n",
+			wantVersion:           "3.13.9",
+			wantDartConstraint:    ">=3.1.0 <3.2.0",
+			wantFlutterConstraint: ">=3.10.0",
+		},
+		{
+			name:                  "Path dependency narrows the Flutter constraint",
+			corePubspec:           "name: core\nenvironment:\n  flutter: ^3.13.0\n",
+			wantVersion:           "3.16.0",
+			wantDartConstraint:    ">=3.0.0 <4.0.0",
+			wantFlutterConstraint: ">=3.13.0",
+		},
+		{
+			name:        "Path dependency makes the Dart range empty",
+			pubspecLock: "sdks:\n  dart: \">=3.1.0 <4.0.0\"\n",
+			corePubspec: "name: core\nenvironment:\n  sdk: ^2.19.0\n",
+			wantErr:     "no dart version satisfies every SDK requirement: path-dependencies (packages/core/pubspec.yaml) requires ^2.19.0, which doesn't allow any version of >=3.1.0 <4.0.0 required by the higher precedence sources",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availableSDKLister := new(mocks.SDKVersionLister)
+			availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]fluttersdk.Release{"stable": {
+				{Channel: "stable", Version: "3.16.0", DartSdkVersion: "3.2.0", Hash: "db7ef5bf9f59442b0e200a90587e8fa5e0c6336a"},
+				{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5", Hash: "d211f42860350d914a5ad8102f9ec32764dc6d06"},
+				{Channel: "stable", Version: "3.10.6", DartSdkVersion: "3.0.6", Hash: "f468f3366c26a5092eb964a230ce7892fda8f2f8"},
+			}}, nil)
+
+			fileOpener := new(mocks.FileManager)
+			fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(func(string) io.Reader { return strings.NewReader(pubspec) }, nil)
+			fileOpener.On("OpenReaderIfExists", "packages/core/pubspec.yaml").Return(func(string) io.Reader { return strings.NewReader(tt.corePubspec) }, nil)
+			if tt.pubspecLock != "" {
+				fileOpener.On("OpenReaderIfExists", "pubspec.lock").Return(func(string) io.Reader { return strings.NewReader(tt.pubspecLock) }, nil)
+			}
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			p := &Project{
+				fileManager:      fileOpener,
+				sdkVersionFinder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister},
+			}
+			release, err := p.FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				var conflictErr SDKRequirementConflictError
+				require.ErrorAs(t, err, &conflictErr)
+				require.Equal(t, PathDependencyVersionSourceName, conflictErr.Source)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantVersion, release.Version)
+
+			resolution, err := p.ResolveSDKVersions()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDartConstraint, resolution.DartVersionConstraint)
+			require.Equal(t, tt.wantFlutterConstraint, resolution.FlutterVersionConstraint)
+		})
+	}
+}
+
+func TestProject_FlutterSDKReleaseToUse_ChannelHeadAndCommitPins(t *testing.T) {
+	tests := []struct {
+		name        string
+		fvmrc       string
+		toolVersion string
+		wantVersion string
+		wantErr     string
+	}{
+		{
+			name:        "FVM channel head",
+			fvmrc:       `{"flutter": "stable"}`,
+			wantVersion: "3.13.8",
+		},
+		{
+			name:        "FVM beta channel head",
+			fvmrc:       `{"flutter": "beta"}`,
+			wantVersion: "3.14.0-0.2.pre",
+		},
+		{
+			name:        "asdf commit",
+			toolVersion: "flutter d211f42860350d914a5ad8102f9ec32764dc6d06",
+			wantVersion: "3.13.9",
+		},
+		{
+			name:    "Master channel head is not released",
+			fvmrc:   `{"flutter": "master"}`,
+			wantErr: "no Flutter SDK release matches the head of the master channel on macos arm64 (channels: master)",
+		},
+		{
+			name:        "Commit is not released",
+			toolVersion: "flutter 0123456789abcdef0123456789abcdef01234567",
+			wantErr:     "no Flutter SDK release matches commit 0123456789abcdef0123456789abcdef01234567 on macos arm64 (channels: stable, beta, dev)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availableSDKLister := new(mocks.SDKVersionLister)
+			availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]fluttersdk.Release{
+				"stable": {
+					{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5", Hash: "d211f42860350d914a5ad8102f9ec32764dc6d06"},
+					// the current release is not necessarily the highest version
+					{Channel: "stable", Version: "3.13.8", DartSdkVersion: "3.1.4", Hash: "6c4930c4ac86fb286f30e31d0ec8bffbcbb9953e", Current: true},
+				},
+				"beta": {
+					{Channel: "beta", Version: "3.14.0-0.2.pre", DartSdkVersion: "3.2.0 (build 3.2.0-42.2.beta)", Hash: "ff5b5b5fa6f35b717667719ddfdb1521d8bdd05a", Current: true},
+				},
+			}, nil)
+
+			fileOpener := new(mocks.FileManager)
+			if tt.fvmrc != "" {
+				fileOpener.On("OpenReaderIfExists", ".fvmrc").Return(strings.NewReader(tt.fvmrc), nil)
+			}
+			if tt.toolVersion != "" {
+				fileOpener.On("OpenReaderIfExists", ".tool-versions").Return(strings.NewReader(tt.toolVersion), nil)
+			}
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			p := &Project{
+				fileManager:      fileOpener,
+				sdkVersionFinder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister},
+			}
+			release, err := p.FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.Nil(t, release)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantVersion, release.Version)
+		})
+	}
+}
+
+// latestReleaseFinder is an SDKVersionFinder, which doesn't implement SDKReleaseLookup.
+type latestReleaseFinder struct {
+	finder fluttersdk.SDKVersionFinder
+}
+
+func (f latestReleaseFinder) FindLatestReleaseFor(platform fluttersdk.Platform, architecture fluttersdk.Architecture, channel fluttersdk.Channel, query fluttersdk.SDKQuery) (*fluttersdk.Release, error) {
+	return f.finder.FindLatestReleaseFor(platform, architecture, channel, query)
+}
+
+func TestProject_FlutterSDKReleaseToUse_WithoutReleaseLookup(t *testing.T) {
+	tests := []struct {
+		name        string
+		fvmrc       string
+		wantVersion string
+		wantErr     string
+	}{
+		{
+			name:        "Channel head resolves to the latest release of the channel",
+			fvmrc:       `{"flutter": "stable"}`,
+			wantVersion: "3.13.9",
+		},
+		{
+			name:    "Commit",
+			fvmrc:   `{"flutter": "d211f42860350d914a5ad8102f9ec32764dc6d06"}`,
+			wantErr: "the SDK version finder can't look up releases by commit: d211f42860350d914a5ad8102f9ec32764dc6d06",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availableSDKLister := new(mocks.SDKVersionLister)
+			availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]fluttersdk.Release{"stable": {
+				{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5", Hash: "d211f42860350d914a5ad8102f9ec32764dc6d06"},
+				{Channel: "stable", Version: "3.13.8", DartSdkVersion: "3.1.4", Hash: "6c4930c4ac86fb286f30e31d0ec8bffbcbb9953e", Current: true},
+			}}, nil)
+
+			fileOpener := new(mocks.FileManager)
+			fileOpener.On("OpenReaderIfExists", ".fvmrc").Return(strings.NewReader(tt.fvmrc), nil)
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			p := &Project{
+				fileManager:      fileOpener,
+				sdkVersionFinder: latestReleaseFinder{finder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister}},
+			}
+			release, err := p.FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantVersion, release.Version)
+		})
+	}
+}
+
+// nilReleaseFinder is an SDKVersionFinder, which returns a nil release instead of a NoMatchingReleaseError.
+type nilReleaseFinder struct{}
+
+func (nilReleaseFinder) FindLatestReleaseFor(fluttersdk.Platform, fluttersdk.Architecture, fluttersdk.Channel, fluttersdk.SDKQuery) (*fluttersdk.Release, error) {
+	return nil, nil
+}
+
+func TestProject_FlutterSDKReleaseToUse_NilRelease(t *testing.T) {
+	fileOpener := new(mocks.FileManager)
+	fileOpener.On("OpenReaderIfExists", ".fvmrc").Return(strings.NewReader(`{"flutter": "3.13.9"}`), nil)
+	fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+	p := &Project{fileManager: fileOpener, sdkVersionFinder: nilReleaseFinder{}}
+	_, err := p.FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+	var noMatchingReleaseErr fluttersdk.NoMatchingReleaseError
+	require.ErrorAs(t, err, &noMatchingReleaseErr)
+	require.EqualError(t, err, "no Flutter SDK release matches Flutter 3.13.9 on macos arm64 (channels: stable, beta, dev)")
+}
+
+func TestProject_FVMFlavors(t *testing.T) {
+	fvmrc := `{
+  "flutter": "3.13.9",
+  "flavors": {
+    "production": "3.13.8",
+    "development": "3.13.9"
+  }
+}`
+	availableSDKLister := new(mocks.SDKVersionLister)
+	availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]fluttersdk.Release{"stable": {
+		{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5"},
+		{Channel: "stable", Version: "3.13.8", DartSdkVersion: "3.1.4"},
+	}}, nil)
+
+	fileOpener := new(mocks.FileManager)
+	fileOpener.On("OpenReaderIfExists", ".fvmrc").Return(func(string) io.Reader { return strings.NewReader(fvmrc) }, nil)
+	fileOpener.On("OpenReaderIfExists", ".tool-versions").Return(nil, nil)
+	fileOpener.On("OpenReaderIfExists", "pubspec.lock").Return(nil, nil)
+	fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(nil, nil)
+	fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+	p := &Project{
+		fileManager:      fileOpener,
+		sdkVersionFinder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister},
+	}
+
+	flavors, err := p.FVMFlavors()
+	require.NoError(t, err)
+	require.Equal(t, []string{"development", "production"}, flavors)
+
+	release, err := p.FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+	require.NoError(t, err)
+	require.Equal(t, "3.13.9", release.Version)
+
+	release, err = p.WithFVMFlavor("production").FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+	require.NoError(t, err)
+	require.Equal(t, "3.13.8", release.Version)
+}
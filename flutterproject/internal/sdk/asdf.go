@@ -5,8 +5,6 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
-
-	"github.com/Masterminds/semver/v3"
 )
 
 const (
@@ -15,7 +13,31 @@ const (
 	masterChannel = "dev"
 )
 
-const asdfConfigRelPath = ".tool-versions"
+const ASDFConfigRelPath = ".tool-versions"
+
+type ASDFVersionKind string
+
+const (
+	// ASDFVersion is a released version, like 3.22.2-stable.
+	ASDFVersion ASDFVersionKind = "version"
+	// ASDFRef is a git ref of the Flutter repository (ref:<commit, tag or branch>).
+	ASDFRef ASDFVersionKind = "ref"
+	// ASDFPath is a local Flutter SDK (path:<dir>).
+	ASDFPath ASDFVersionKind = "path"
+	// ASDFSystem is the Flutter SDK installed outside of asdf.
+	ASDFSystem ASDFVersionKind = "system"
+)
+
+/*
+ASDFVersionCandidate is a single version of a .tool-versions line, asdf uses the first installed one of the listed versions.
+
+Pin is the Flutter SDK the candidate refers to, it is nil for local SDKs (path: and system versions).
+*/
+type ASDFVersionCandidate struct {
+	Kind  ASDFVersionKind
+	Value string
+	Pin   *FlutterPin
+}
 
 type ASDFVersionReader struct {
 	fileOpener FileOpener
@@ -27,57 +49,134 @@ func NewASDFVersionReader(fileOpener FileOpener) ASDFVersionReader {
 	}
 }
 
-func (r ASDFVersionReader) ReadSDKVersions(projectRootDir string) (*semver.Version, string, error) {
-	asdfConfigPth := filepath.Join(projectRootDir, asdfConfigRelPath)
-	f, err := r.fileOpener.OpenReaderIfExists(asdfConfigPth)
+// ReadSDKVersions returns the Flutter SDK pinned by the first asdf version candidate, which refers to a Flutter release,
+// and the project relative path of the .tool-versions file it was read from.
+func (r ASDFVersionReader) ReadSDKVersions(projectRootDir string) (*FlutterPin, string, error) {
+	candidates, configRelPath, err := r.ReadCandidates(projectRootDir)
 	if err != nil {
 		return nil, "", err
 	}
 
-	if f == nil {
-		return nil, "", nil
+	for _, candidate := range candidates {
+		if candidate.Pin != nil {
+			return candidate.Pin, configRelPath, nil
+		}
 	}
 
-	versionStr, channel, err := parseASDFFlutterVersion(f)
-	if err != nil {
-		return nil, "", err
-	}
-	if versionStr == "" {
-		return nil, "", nil
-	}
+	return nil, configRelPath, nil
+}
 
-	version, err := semver.NewVersion(versionStr)
-	if err != nil {
-		return nil, "", err
+/*
+ReadCandidates returns the ordered Flutter version candidates of the closest .tool-versions file, which lists flutter,
+and the project relative path of the file.
+
+Like asdf, the lookup starts in the project root dir and continues in the parent directories,
+so the root dir should be an absolute path for the lookup to reach the parent directories.
+*/
+func (r ASDFVersionReader) ReadCandidates(projectRootDir string) ([]ASDFVersionCandidate, string, error) {
+	dir := filepath.Clean(projectRootDir)
+	for {
+		asdfConfigPth := filepath.Join(dir, ASDFConfigRelPath)
+		f, err := r.fileOpener.OpenReaderIfExists(asdfConfigPth)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if f != nil {
+			versions, err := parseASDFFlutterVersions(f)
+			if err != nil {
+				return nil, "", err
+			}
+
+			if len(versions) > 0 {
+				candidates, err := parseASDFVersionCandidates(versions)
+				if err != nil {
+					return nil, "", err
+				}
+
+				configRelPath, err := filepath.Rel(filepath.Clean(projectRootDir), asdfConfigPth)
+				if err != nil {
+					return nil, "", err
+				}
+
+				return candidates, configRelPath, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
 	}
 
-	return version, channel, nil
+	return nil, "", nil
 }
 
-func parseASDFFlutterVersion(asdfConfigReader io.Reader) (string, string, error) {
-	versionStr := ""
-	channel := ""
-
+// parseASDFFlutterVersions returns the versions listed on the first flutter line, comments are ignored.
+func parseASDFFlutterVersions(asdfConfigReader io.Reader) ([]string, error) {
 	scanner := bufio.NewScanner(asdfConfigReader)
 	scanner.Split(bufio.ScanLines)
 	for scanner.Scan() {
 		line := scanner.Text()
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "flutter ") {
-			versionStr = strings.TrimPrefix(line, "flutter ")
-			for _, c := range []string{stableChannel, betaChannel, masterChannel} {
-				channelSuffix := "-" + c
-				if strings.HasSuffix(versionStr, channelSuffix) {
-					versionStr = strings.TrimSuffix(versionStr, channelSuffix)
-					channel = c
-				}
-			}
-			break
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[0] == "flutter" {
+			return fields[1:], nil
 		}
 	}
 	if err := scanner.Err(); err != nil {
-		return "", "", err
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func parseASDFVersionCandidates(versions []string) ([]ASDFVersionCandidate, error) {
+	var candidates []ASDFVersionCandidate
+	for _, version := range versions {
+		candidate, err := parseASDFVersionCandidate(version)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+func parseASDFVersionCandidate(version string) (ASDFVersionCandidate, error) {
+	switch {
+	case version == "system":
+		return ASDFVersionCandidate{Kind: ASDFSystem, Value: version}, nil
+	case strings.HasPrefix(version, "path:"):
+		return ASDFVersionCandidate{Kind: ASDFPath, Value: strings.TrimPrefix(version, "path:")}, nil
+	case strings.HasPrefix(version, "ref:"):
+		ref := strings.TrimPrefix(version, "ref:")
+		pin, err := parseFlutterPin(splitChannelSuffix(ref))
+		if err != nil {
+			// branches other than the channels can't be resolved to a release
+			pin = nil
+		}
+		return ASDFVersionCandidate{Kind: ASDFRef, Value: ref, Pin: pin}, nil
+	default:
+		pin, err := parseFlutterPin(splitChannelSuffix(version))
+		if err != nil {
+			return ASDFVersionCandidate{}, err
+		}
+		return ASDFVersionCandidate{Kind: ASDFVersion, Value: version, Pin: pin}, nil
 	}
+}
 
-	return versionStr, channel, nil
+// splitChannelSuffix splits the asdf-flutter plugin's version format, like 3.13.6-stable.
+func splitChannelSuffix(versionStr string) (string, string) {
+	for _, c := range []string{stableChannel, betaChannel, masterChannel} {
+		channelSuffix := "-" + c
+		if strings.HasSuffix(versionStr, channelSuffix) {
+			return strings.TrimSuffix(versionStr, channelSuffix), c
+		}
+	}
+	return versionStr, ""
 }
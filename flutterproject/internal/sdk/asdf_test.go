@@ -6,27 +6,39 @@ import (
 	"testing"
 
 	"github.com/bitrise-io/go-flutter/flutterproject/internal/testassets"
+	"github.com/bitrise-io/go-flutter/mocks"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
-func Test_parseASDFFlutterVersion(t *testing.T) {
+func Test_parseASDFFlutterVersions(t *testing.T) {
 	tests := []struct {
 		name             string
 		asdfConfigReader io.Reader
-		wantFlutterSDK   string
-		wantChannel      string
+		wantVersions     []string
 		wantErr          string
 	}{
 		{
 			name:             "Real .tool-versions",
 			asdfConfigReader: strings.NewReader(testassets.ToolVersions),
-			wantFlutterSDK:   "3.7.12",
+			wantVersions:     []string{"3.7.12"},
 		},
 		{
 			name:             "Real .tool-versions with channel",
 			asdfConfigReader: strings.NewReader("flutter 3.13.6-stable"),
-			wantFlutterSDK:   "3.13.6",
-			wantChannel:      "stable",
+			wantVersions:     []string{"3.13.6-stable"},
+		},
+		{
+			name: "Fallback versions and comments",
+			asdfConfigReader: strings.NewReader(`# flutter 3.0.0
+ruby 3.3.0
+flutter   3.22.2-stable 3.19.6-stable	system # fallbacks
+`),
+			wantVersions: []string{"3.22.2-stable", "3.19.6-stable", "system"},
+		},
+		{
+			name:             "Other tools only",
+			asdfConfigReader: strings.NewReader("nodejs 20.11.0\nflutter-tools 1.0.0"),
 		},
 		{
 			name:             "Empty .tool-versions",
@@ -36,15 +48,174 @@ func Test_parseASDFFlutterVersion(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotFlutterSDK, gotChannel, err := parseASDFFlutterVersion(tt.asdfConfigReader)
+			gotVersions, err := parseASDFFlutterVersions(tt.asdfConfigReader)
 			if tt.wantErr != "" {
 				require.EqualError(t, err, tt.wantErr)
-				require.Empty(t, gotFlutterSDK)
+				require.Empty(t, gotVersions)
 			} else {
 				require.NoError(t, err)
-				require.Equal(t, tt.wantFlutterSDK, gotFlutterSDK)
-				require.Equal(t, tt.wantChannel, gotChannel)
+				require.Equal(t, tt.wantVersions, gotVersions)
+			}
+		})
+	}
+}
+
+func Test_parseASDFVersionCandidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		wantKind    ASDFVersionKind
+		wantValue   string
+		wantVersion string
+		wantChannel string
+		wantCommit  string
+		wantNoPin   bool
+		wantErr     string
+	}{
+		{
+			name:        "Version with channel",
+			version:     "3.22.2-stable",
+			wantKind:    ASDFVersion,
+			wantValue:   "3.22.2-stable",
+			wantVersion: "3.22.2",
+			wantChannel: "stable",
+		},
+		{
+			name:       "Commit ref",
+			version:    "ref:761747bfc538b5af34aa0d3fac380f1bc331ec49",
+			wantKind:   ASDFRef,
+			wantValue:  "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+			wantCommit: "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+		},
+		{
+			name:        "Channel ref",
+			version:     "ref:beta",
+			wantKind:    ASDFRef,
+			wantValue:   "beta",
+			wantChannel: "beta",
+		},
+		{
+			name:        "Tag ref",
+			version:     "ref:3.22.2",
+			wantKind:    ASDFRef,
+			wantValue:   "3.22.2",
+			wantVersion: "3.22.2",
+		},
+		{
+			name:      "Branch ref",
+			version:   "ref:my-feature",
+			wantKind:  ASDFRef,
+			wantValue: "my-feature",
+			wantNoPin: true,
+		},
+		{
+			name:      "Local SDK",
+			version:   "path:/opt/flutter",
+			wantKind:  ASDFPath,
+			wantValue: "/opt/flutter",
+			wantNoPin: true,
+		},
+		{
+			name:      "System SDK",
+			version:   "system",
+			wantKind:  ASDFSystem,
+			wantValue: "system",
+			wantNoPin: true,
+		},
+		{
+			name:    "Invalid version",
+			version: "latest:3.22",
+			wantErr: "Invalid Semantic Version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseASDFVersionCandidate(tt.version)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantKind, got.Kind)
+			require.Equal(t, tt.wantValue, got.Value)
+			if tt.wantNoPin {
+				require.Nil(t, got.Pin)
+				return
+			}
+
+			require.NotNil(t, got.Pin)
+			if tt.wantVersion == "" {
+				require.Nil(t, got.Pin.Version)
+			} else {
+				require.Equal(t, tt.wantVersion, got.Pin.Version.String())
+			}
+			require.Equal(t, tt.wantChannel, got.Pin.Channel)
+			require.Equal(t, tt.wantCommit, got.Pin.Commit)
+		})
+	}
+}
+
+func TestASDFVersionReader_ReadSDKVersions(t *testing.T) {
+	tests := []struct {
+		name           string
+		files          map[string]string
+		wantFlutterSDK string
+		wantConfigPath string
+	}{
+		{
+			name: "Project .tool-versions",
+			files: map[string]string{
+				"/repo/app/.tool-versions": "flutter 3.22.2-stable",
+				"/repo/.tool-versions":     "flutter 3.19.6-stable",
+			},
+			wantFlutterSDK: "3.22.2",
+			wantConfigPath: ".tool-versions",
+		},
+		{
+			name: "Monorepo root .tool-versions",
+			files: map[string]string{
+				"/repo/app/.tool-versions": "ruby 3.3.0",
+				"/repo/.tool-versions":     "flutter 3.19.6-stable",
+			},
+			wantFlutterSDK: "3.19.6",
+			wantConfigPath: "../.tool-versions",
+		},
+		{
+			name: "First fallback, which refers to a release",
+			files: map[string]string{
+				"/repo/app/.tool-versions": "flutter path:/opt/flutter system 3.16.9",
+			},
+			wantFlutterSDK: "3.16.9",
+			wantConfigPath: ".tool-versions",
+		},
+		{
+			name: "Local SDK only",
+			files: map[string]string{
+				"/repo/app/.tool-versions": "flutter system",
+			},
+			wantConfigPath: ".tool-versions",
+		},
+		{
+			name:  "No .tool-versions",
+			files: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileOpener := new(mocks.FileManager)
+			for pth, content := range tt.files {
+				fileOpener.On("OpenReaderIfExists", pth).Return(strings.NewReader(content), nil)
+			}
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			gotPin, gotConfigPath, err := NewASDFVersionReader(fileOpener).ReadSDKVersions("/repo/app")
+			require.NoError(t, err)
+			if tt.wantFlutterSDK == "" {
+				require.Nil(t, gotPin)
+			} else {
+				require.Equal(t, tt.wantFlutterSDK, gotPin.Version.String())
 			}
+			require.Equal(t, tt.wantConfigPath, gotConfigPath)
 		})
 	}
 }
@@ -0,0 +1,100 @@
+package sdk
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	BitriseConfigRelPath = "bitrise.yml"
+	flutterInstallerStep = "flutter-installer"
+)
+
+type BitriseVersionReader struct {
+	fileOpener FileOpener
+}
+
+func NewBitriseVersionReader(fileOpener FileOpener) BitriseVersionReader {
+	return BitriseVersionReader{
+		fileOpener: fileOpener,
+	}
+}
+
+// ReadSDKVersion returns the Flutter version and channel installed by the first flutter-installer step of the project's Bitrise workflows.
+func (r BitriseVersionReader) ReadSDKVersion(projectRootDir string) (*VersionConstraint, string, error) {
+	bitriseConfigPth := filepath.Join(projectRootDir, BitriseConfigRelPath)
+	f, err := r.fileOpener.OpenReaderIfExists(bitriseConfigPth)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if f == nil {
+		return nil, "", nil
+	}
+
+	versionStr, err := parseBitriseFlutterVersion(f)
+	if err != nil {
+		return nil, "", err
+	}
+
+	version, channel := parseCIFlutterVersion(versionStr)
+	return version, channel, nil
+}
+
+// parseBitriseFlutterVersion returns the version input of the first flutter-installer step, which sets it.
+// The step is matched by its ID, regardless of the step library and version (flutter-installer@0, git::https://...flutter-installer.git@master).
+func parseBitriseFlutterVersion(bitriseConfigReader io.Reader) (string, error) {
+	type bitriseStep struct {
+		Inputs []map[string]yaml.Node `yaml:"inputs"`
+	}
+	type bitriseWorkflow struct {
+		Steps []map[string]bitriseStep `yaml:"steps"`
+	}
+	type bitriseConfig struct {
+		Workflows yaml.Node `yaml:"workflows"`
+	}
+
+	var config bitriseConfig
+	d := yaml.NewDecoder(bitriseConfigReader)
+	if err := d.Decode(&config); err != nil {
+		if err == io.EOF {
+			return "", nil
+		}
+		return "", err
+	}
+
+	for _, workflowNode := range mappingValues(&config.Workflows) {
+		var workflow bitriseWorkflow
+		if err := workflowNode.Decode(&workflow); err != nil {
+			return "", err
+		}
+
+		for _, stepByID := range workflow.Steps {
+			for stepID, step := range stepByID {
+				if !isFlutterInstallerStep(stepID) {
+					continue
+				}
+
+				for _, input := range step.Inputs {
+					if version, ok := input["version"]; ok && version.Kind == yaml.ScalarNode && version.Value != "" {
+						return version.Value, nil
+					}
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func isFlutterInstallerStep(stepID string) bool {
+	stepID = strings.TrimPrefix(stepID, "git::")
+	if idx := strings.LastIndex(stepID, "@"); idx != -1 && !strings.ContainsAny(stepID[idx:], ":/") {
+		stepID = stepID[:idx]
+	}
+	stepID = strings.TrimSuffix(stepID, ".git")
+	return stepID == flutterInstallerStep || strings.HasSuffix(stepID, "/"+flutterInstallerStep) || strings.HasSuffix(stepID, "-"+flutterInstallerStep)
+}
@@ -0,0 +1,101 @@
+package sdk
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseBitriseFlutterVersion(t *testing.T) {
+	tests := []struct {
+		name                string
+		bitriseConfigReader io.Reader
+		wantFlutterSDK      string
+		wantErr             string
+	}{
+		{
+			name: "Version",
+			bitriseConfigReader: strings.NewReader(`format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+workflows:
+  primary:
+    steps:
+    - git-clone@8: {}
+    - flutter-installer@0:
+        inputs:
+        - is_update: "false"
+        - version: 3.22.2
+    - flutter-test@1: {}
+`),
+			wantFlutterSDK: "3.22.2",
+		},
+		{
+			name: "Step with input options",
+			bitriseConfigReader: strings.NewReader(`workflows:
+  primary:
+    steps:
+    - flutter-installer@0.17:
+        inputs:
+        - version: beta
+          opts:
+            is_expand: true
+`),
+			wantFlutterSDK: "beta",
+		},
+		{
+			name: "Step referenced by git URL",
+			bitriseConfigReader: strings.NewReader(`workflows:
+  primary:
+    steps:
+    - git::https://github.com/bitrise-steplib/bitrise-step-flutter-installer.git@master:
+        inputs:
+        - version: 3.19.6-stable
+`),
+			wantFlutterSDK: "3.19.6-stable",
+		},
+		{
+			name: "Step without version input",
+			bitriseConfigReader: strings.NewReader(`workflows:
+  primary:
+    steps:
+    - flutter-installer@0: {}
+  deploy:
+    steps:
+    - flutter-installer@0:
+        inputs:
+        - version: 3.22.2
+`),
+			wantFlutterSDK: "3.22.2",
+		},
+		{
+			name: "No flutter-installer step",
+			bitriseConfigReader: strings.NewReader(`workflows:
+  primary:
+    steps:
+    - flutter-build@0: {}
+`),
+		},
+		{
+			name:                "Empty config",
+			bitriseConfigReader: strings.NewReader(""),
+		},
+		{
+			name:                "Invalid config",
+			bitriseConfigReader: strings.NewReader("workflows: ["),
+			wantErr:             "yaml: line 1: did not find expected node content",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFlutterSDK, err := parseBitriseFlutterVersion(tt.bitriseConfigReader)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantFlutterSDK, gotFlutterSDK)
+			}
+		})
+	}
+}
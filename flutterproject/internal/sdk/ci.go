@@ -0,0 +1,135 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+type DirEntryReader interface {
+	ReadDirEntryNames(path string) ([]string, error)
+}
+
+/*
+parseCIFlutterVersion interprets the Flutter version input of a CI integration (GitHub Actions, Codemagic, Bitrise).
+
+The input is interpreted as:
+- a channel, for channel names (stable, beta, master)
+- a version, for exact versions, optionally with a channel suffix (3.22.2, v3.22.2, 3.22.2-stable)
+- a constraint, for partial and wildcard versions (3.22, 3.22.x, 3.x)
+- nothing, for any, latest, wildcard-only versions (x, *, x.x.x), CI expressions (${{ matrix.flutter }}) and anything else (like an SDK archive URL)
+*/
+func parseCIFlutterVersion(value string) (*VersionConstraint, string) {
+	value = strings.TrimSpace(value)
+
+	switch value {
+	case stableChannel, betaChannel, "master", "main":
+		return nil, value
+	case "", "any", "latest":
+		return nil, ""
+	}
+
+	versionStr, channel := splitChannelSuffix(strings.TrimPrefix(value, "v"))
+	if version, err := semver.StrictNewVersion(versionStr); err == nil {
+		return &VersionConstraint{Version: version}, channel
+	}
+
+	constraint := partialVersionConstraint(versionStr)
+	if constraint == nil {
+		return nil, ""
+	}
+	if constraint.IsAny() {
+		// Wildcard-only versions don't narrow down the versions, just like any and latest.
+		return nil, channel
+	}
+	return &VersionConstraint{Constraint: constraint}, channel
+}
+
+/*
+parseToolFlutterVersion interprets the Flutter version of a version manager, which accepts version requirements (mise, proto).
+
+The value is interpreted like the input of a CI integration (see parseCIFlutterVersion), in addition ranges are constraints:
+tilde ranges (~3.22 and ~3.22.1 allow the patch releases of 3.22), caret ranges (^3.22 = ^3.22.0) and pub constraints (>=3.22.0 <3.24.0).
+Anything else means no requirement.
+*/
+func parseToolFlutterVersion(value string) (*VersionConstraint, string) {
+	if constraint, channel := parseCIFlutterVersion(value); constraint != nil || channel != "" {
+		return constraint, channel
+	}
+
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "~") {
+		versionStr := strings.TrimSpace(strings.TrimPrefix(value, "~"))
+		if version, err := semver.StrictNewVersion(versionStr); err == nil {
+			max := semver.New(version.Major(), version.Minor()+1, 0, "", "")
+			return &VersionConstraint{Constraint: withText(NewVersionRange(version, true, max, false), value)}, ""
+		}
+		if constraint := partialVersionConstraint(versionStr); constraint != nil && !constraint.IsAny() {
+			return &VersionConstraint{Constraint: withText(constraint, value)}, ""
+		}
+		return nil, ""
+	}
+	if strings.HasPrefix(value, "^") && strings.Count(value, ".") < 2 {
+		value += strings.Repeat(".0", 2-strings.Count(value, "."))
+	}
+
+	constraint, err := ParseConstraint(value)
+	if err != nil {
+		return nil, ""
+	}
+	return &VersionConstraint{Constraint: constraint}, ""
+}
+
+/*
+partialVersionConstraint returns the constraint of a version with missing or wildcard parts,
+like 3.22 or 3.22.x (>=3.22.0 <3.23.0) and 3.x (>=3.0.0 <4.0.0), or nil if the value is not a partial version.
+*/
+func partialVersionConstraint(value string) *Constraint {
+	parts := strings.Split(value, ".")
+	if len(parts) > 3 {
+		return nil
+	}
+
+	var numbers []uint64
+	wildcard := false
+	for _, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			// 3.x.1
+			return nil
+		}
+		number, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil
+		}
+		numbers = append(numbers, number)
+	}
+
+	var min, max *semver.Version
+	switch len(numbers) {
+	case 0:
+		return withText(AnyConstraint(), value)
+	case 1:
+		min, max = semver.New(numbers[0], 0, 0, "", ""), semver.New(numbers[0]+1, 0, 0, "", "")
+	case 2:
+		min, max = semver.New(numbers[0], numbers[1], 0, "", ""), semver.New(numbers[0], numbers[1]+1, 0, "", "")
+	default:
+		return withText(ExactVersionConstraint(semver.New(numbers[0], numbers[1], numbers[2], "", "")), value)
+	}
+	return withText(NewVersionRange(min, true, max, false), value)
+}
+
+// mergeCIChannel combines the channel input of a CI integration with the channel parsed from its version input.
+func mergeCIChannel(versionChannel, channel string) string {
+	if versionChannel != "" {
+		return versionChannel
+	}
+	if channel == "any" {
+		return ""
+	}
+	return channel
+}
@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseCIFlutterVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		wantVersion    string
+		wantConstraint string
+		wantChannel    string
+	}{
+		{
+			name:        "Exact version",
+			value:       "3.22.2",
+			wantVersion: "3.22.2",
+		},
+		{
+			name:        "Exact version with v prefix",
+			value:       "v3.22.2",
+			wantVersion: "3.22.2",
+		},
+		{
+			name:        "Exact version with channel",
+			value:       "3.22.2-stable",
+			wantVersion: "3.22.2",
+			wantChannel: "stable",
+		},
+		{
+			name:        "Pre-release version",
+			value:       "3.23.0-0.1.pre",
+			wantVersion: "3.23.0-0.1.pre",
+		},
+		{
+			name:           "Partial version",
+			value:          "3.22",
+			wantConstraint: "3.22",
+		},
+		{
+			name:           "Wildcard version",
+			value:          "3.x",
+			wantConstraint: "3.x",
+		},
+		{
+			name:        "Channel",
+			value:       "beta",
+			wantChannel: "beta",
+		},
+		{
+			name:        "Master channel",
+			value:       "master",
+			wantChannel: "master",
+		},
+		{
+			name:  "Any version",
+			value: "any",
+		},
+		{
+			name:  "Wildcard-only version",
+			value: "x",
+		},
+		{
+			name:  "Wildcard-only version with all parts",
+			value: "*.*.*",
+		},
+		{
+			name:        "Wildcard-only version with channel",
+			value:       "x-beta",
+			wantChannel: "beta",
+		},
+		{
+			name:  "Expression",
+			value: "${{ matrix.flutter-version }}",
+		},
+		{
+			name:  "Codemagic default version",
+			value: "default",
+		},
+		{
+			name:  "SDK archive URL",
+			value: "https://storage.googleapis.com/flutter_infra_release/releases/stable/macos/flutter_macos_3.22.2-stable.zip",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotChannel := parseCIFlutterVersion(tt.value)
+			require.Equal(t, tt.wantChannel, gotChannel)
+
+			if tt.wantVersion == "" && tt.wantConstraint == "" {
+				require.Nil(t, got)
+				return
+			}
+
+			require.NotNil(t, got)
+			if tt.wantVersion != "" {
+				require.Equal(t, tt.wantVersion, got.Version.String())
+			} else {
+				require.Nil(t, got.Version)
+			}
+			if tt.wantConstraint != "" {
+				require.Equal(t, tt.wantConstraint, got.Constraint.String())
+			} else {
+				require.Nil(t, got.Constraint)
+			}
+		})
+	}
+}
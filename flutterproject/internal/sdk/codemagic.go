@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"io"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const CodemagicConfigRelPath = "codemagic.yaml"
+
+type CodemagicVersionReader struct {
+	fileOpener FileOpener
+}
+
+func NewCodemagicVersionReader(fileOpener FileOpener) CodemagicVersionReader {
+	return CodemagicVersionReader{
+		fileOpener: fileOpener,
+	}
+}
+
+// ReadSDKVersion returns the Flutter version and channel set in the environment of the first Codemagic workflow, which defines it.
+func (r CodemagicVersionReader) ReadSDKVersion(projectRootDir string) (*VersionConstraint, string, error) {
+	codemagicConfigPth := filepath.Join(projectRootDir, CodemagicConfigRelPath)
+	f, err := r.fileOpener.OpenReaderIfExists(codemagicConfigPth)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if f == nil {
+		return nil, "", nil
+	}
+
+	versionStr, err := parseCodemagicFlutterVersion(f)
+	if err != nil {
+		return nil, "", err
+	}
+
+	version, channel := parseCIFlutterVersion(versionStr)
+	return version, channel, nil
+}
+
+// parseCodemagicFlutterVersion returns the first workflow's environment.flutter value.
+// Besides versions and channels, Codemagic accepts default (the preinstalled SDK) and fvm (the project's FVM config),
+// these are returned as is and are not considered as requirements.
+func parseCodemagicFlutterVersion(codemagicConfigReader io.Reader) (string, error) {
+	type codemagicWorkflow struct {
+		Environment struct {
+			Flutter string `yaml:"flutter"`
+		} `yaml:"environment"`
+	}
+	type codemagicConfig struct {
+		Workflows yaml.Node `yaml:"workflows"`
+	}
+
+	var config codemagicConfig
+	d := yaml.NewDecoder(codemagicConfigReader)
+	if err := d.Decode(&config); err != nil {
+		if err == io.EOF {
+			return "", nil
+		}
+		return "", err
+	}
+
+	for _, workflowNode := range mappingValues(&config.Workflows) {
+		var workflow codemagicWorkflow
+		if err := workflowNode.Decode(&workflow); err != nil {
+			return "", err
+		}
+
+		if workflow.Environment.Flutter != "" {
+			return workflow.Environment.Flutter, nil
+		}
+	}
+
+	return "", nil
+}
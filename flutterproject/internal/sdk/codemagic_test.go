@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseCodemagicFlutterVersion(t *testing.T) {
+	tests := []struct {
+		name                  string
+		codemagicConfigReader io.Reader
+		wantFlutterSDK        string
+		wantErr               string
+	}{
+		{
+			name: "Version",
+			codemagicConfigReader: strings.NewReader(`workflows:
+  android-workflow:
+    name: Android Workflow
+    instance_type: mac_mini_m2
+    environment:
+      flutter: 3.22.2
+      xcode: latest
+    scripts:
+      - flutter build apk
+`),
+			wantFlutterSDK: "3.22.2",
+		},
+		{
+			name: "First workflow with a Flutter version",
+			codemagicConfigReader: strings.NewReader(`workflows:
+  web-workflow:
+    environment:
+      node: 20
+  ios-workflow:
+    environment:
+      flutter: beta
+  android-workflow:
+    environment:
+      flutter: stable
+`),
+			wantFlutterSDK: "beta",
+		},
+		{
+			name: "No Flutter version",
+			codemagicConfigReader: strings.NewReader(`workflows:
+  android-workflow:
+    scripts:
+      - flutter build apk
+`),
+		},
+		{
+			name:                  "Empty config",
+			codemagicConfigReader: strings.NewReader(""),
+		},
+		{
+			name:                  "Invalid config",
+			codemagicConfigReader: strings.NewReader("workflows: ["),
+			wantErr:               "yaml: line 1: did not find expected node content",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFlutterSDK, err := parseCodemagicFlutterVersion(tt.codemagicConfigReader)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantFlutterSDK, gotFlutterSDK)
+			}
+		})
+	}
+}
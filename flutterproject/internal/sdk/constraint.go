@@ -0,0 +1,607 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const versionPattern = `(\d+)\.(\d+)\.(\d+)(-([0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*))?(\+([0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*))?`
+
+var (
+	startVersionExp    = regexp.MustCompile(`^` + versionPattern)
+	completeVersionExp = regexp.MustCompile(`^` + versionPattern + `$`)
+	startComparisonExp = regexp.MustCompile(`^[<>]=?`)
+)
+
+/*
+Constraint is a version constraint with the semantics of Dart's pub (the pub_semver package),
+which differ from the Masterminds semver constraints in a few cases:
+- a bare version (1.2.3) only allows the exact version, including its build metadata (1.2.3+1 is a different version)
+- ^1.2.3 means >=1.2.3 <2.0.0, ^0.1.2 means >=0.1.2 <0.2.0 and ^0.0.3 means >=0.0.3 <0.1.0
+- pre-releases in the range are allowed, but an exclusive max (<2.0.0) excludes its pre-releases (2.0.0-dev)
+- build metadata takes part in the ordering: 1.0.0 < 1.0.0+1 < 1.0.1-0
+
+The pre-releases of an exclusive max are only allowed if the min is a pre-release of it too (>=2.0.0-dev <2.0.0).
+
+A constraint is a union of non-overlapping version ranges: no range means the constraint allows nothing (empty),
+a single unbounded range means it allows any version.
+*/
+type Constraint struct {
+	ranges []versionRange
+	text   string
+}
+
+type versionRange struct {
+	min, max               *semver.Version
+	includeMin, includeMax bool
+}
+
+// AnyConstraint returns the constraint, which allows every version.
+func AnyConstraint() *Constraint {
+	return &Constraint{ranges: []versionRange{{}}}
+}
+
+// EmptyConstraint returns the constraint, which allows no version.
+func EmptyConstraint() *Constraint {
+	return &Constraint{}
+}
+
+// ExactVersionConstraint returns the constraint, which allows the given version only.
+func ExactVersionConstraint(version *semver.Version) *Constraint {
+	return &Constraint{ranges: []versionRange{{min: version, max: version, includeMin: true, includeMax: true}}}
+}
+
+/*
+NewVersionRange returns the constraint allowing the versions between min and max, a nil min or max means no bound.
+
+Like pub's VersionRange, an exclusive max, which is not a pre-release, excludes the pre-releases of max too
+(unless min is a pre-release of max).
+*/
+func NewVersionRange(min *semver.Version, includeMin bool, max *semver.Version, includeMax bool) *Constraint {
+	if min != nil && max != nil {
+		switch cmp := compareVersions(min, max); {
+		case cmp > 0:
+			return EmptyConstraint()
+		case cmp == 0:
+			if includeMin && includeMax {
+				return ExactVersionConstraint(min)
+			}
+			return EmptyConstraint()
+		}
+	}
+
+	if !includeMax && max != nil && max.Prerelease() == "" && max.Metadata() == "" &&
+		(min == nil || min.Prerelease() == "" || !equalsWithoutPreRelease(min, max)) {
+		max = firstPreRelease(max)
+	}
+
+	return &Constraint{ranges: []versionRange{{min: min, max: max, includeMin: includeMin, includeMax: includeMax}}}
+}
+
+// CompatibleWithConstraint returns the constraint of ^version: the versions which are backward compatible with the given version.
+func CompatibleWithConstraint(version *semver.Version) *Constraint {
+	return &Constraint{
+		ranges: []versionRange{{min: version, max: firstPreRelease(nextBreaking(version)), includeMin: true}},
+		text:   "^" + version.String(),
+	}
+}
+
+/*
+ParseConstraint parses a pub version constraint, which is one of:
+- any
+- a version (1.2.3)
+- a caret constraint (^1.2.3)
+- a list of comparisons and versions, which all have to be satisfied (>=1.2.3 <2.0.0)
+*/
+func ParseConstraint(text string) (*Constraint, error) {
+	originalText := text
+	text = strings.TrimSpace(text)
+
+	if text == "any" {
+		return withText(AnyConstraint(), text), nil
+	}
+
+	if strings.HasPrefix(text, "^") {
+		text = strings.TrimSpace(strings.TrimPrefix(text, "^"))
+		versionStr := startVersionExp.FindString(text)
+		if versionStr == "" {
+			return nil, fmt.Errorf(`expected version number after "^" in "%s", got "%s"`, originalText, text)
+		}
+		if strings.TrimSpace(strings.TrimPrefix(text, versionStr)) != "" {
+			return nil, fmt.Errorf(`cannot include other constraints with "^" constraint in "%s"`, originalText)
+		}
+
+		version, err := semver.NewVersion(versionStr)
+		if err != nil {
+			return nil, err
+		}
+		return withText(CompatibleWithConstraint(version), strings.TrimSpace(originalText)), nil
+	}
+
+	var bounds versionRange
+	for {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			break
+		}
+
+		r, rest, err := parseComparison(originalText, text)
+		if err != nil {
+			return nil, err
+		}
+		text = rest
+
+		if r.min != nil {
+			if bounds.min == nil || compareVersions(r.min, bounds.min) > 0 {
+				bounds.min, bounds.includeMin = r.min, r.includeMin
+			} else if compareVersions(r.min, bounds.min) == 0 && !r.includeMin {
+				bounds.includeMin = false
+			}
+		}
+		if r.max != nil {
+			if bounds.max == nil || compareVersions(r.max, bounds.max) < 0 {
+				bounds.max, bounds.includeMax = r.max, r.includeMax
+			} else if compareVersions(r.max, bounds.max) == 0 && !r.includeMax {
+				bounds.includeMax = false
+			}
+		}
+	}
+
+	if bounds.min == nil && bounds.max == nil {
+		return nil, fmt.Errorf("cannot parse an empty string")
+	}
+
+	return withText(NewVersionRange(bounds.min, bounds.includeMin, bounds.max, bounds.includeMax), strings.TrimSpace(originalText)), nil
+}
+
+// withText sets the text the constraint was parsed from.
+func withText(c *Constraint, text string) *Constraint {
+	c.text = text
+	return c
+}
+
+// parseComparison parses a version or a comparison operator followed by a version from the beginning of the text.
+func parseComparison(originalText, text string) (versionRange, string, error) {
+	if versionStr := startVersionExp.FindString(text); versionStr != "" {
+		version, err := semver.NewVersion(versionStr)
+		if err != nil {
+			return versionRange{}, "", err
+		}
+		return versionRange{min: version, max: version, includeMin: true, includeMax: true}, text[len(versionStr):], nil
+	}
+
+	op := startComparisonExp.FindString(text)
+	if op == "" {
+		return versionRange{}, "", fmt.Errorf(`could not parse version "%s", unknown text at "%s"`, originalText, text)
+	}
+
+	text = strings.TrimSpace(text[len(op):])
+	versionStr := startVersionExp.FindString(text)
+	if versionStr == "" {
+		return versionRange{}, "", fmt.Errorf(`expected version number after "%s" in "%s", got "%s"`, op, originalText, text)
+	}
+	version, err := semver.NewVersion(versionStr)
+	if err != nil {
+		return versionRange{}, "", err
+	}
+	rest := text[len(versionStr):]
+
+	switch op {
+	case "<=":
+		return versionRange{max: version, includeMax: true}, rest, nil
+	case "<":
+		return versionRange{max: version}, rest, nil
+	case ">=":
+		return versionRange{min: version, includeMin: true}, rest, nil
+	default:
+		return versionRange{min: version}, rest, nil
+	}
+}
+
+// LowerBound returns the constraint with the lowest allowed version as its only bound, any upper bound (and gap) is dropped.
+func (c *Constraint) LowerBound() *Constraint {
+	if c.IsEmpty() {
+		return EmptyConstraint()
+	}
+	lowest := c.ranges[0]
+	return &Constraint{ranges: []versionRange{{min: lowest.min, includeMin: lowest.includeMin}}}
+}
+
+// MinVersion returns the lowest bound of the constraint, nil if the constraint has no lower bound or allows nothing.
+func (c *Constraint) MinVersion() *semver.Version {
+	if c.IsEmpty() {
+		return nil
+	}
+	return c.ranges[0].min
+}
+
+// Equal tells whether the two constraints allow the same versions.
+func (c *Constraint) Equal(other *Constraint) bool {
+	return c.AllowsAll(other) && other.AllowsAll(c)
+}
+
+// Check tells whether the constraint allows the version.
+func (c *Constraint) Check(version *semver.Version) bool {
+	for _, r := range c.ranges {
+		if r.allows(version) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Constraint) IsAny() bool {
+	return len(c.ranges) == 1 && c.ranges[0].min == nil && c.ranges[0].max == nil
+}
+
+func (c *Constraint) IsEmpty() bool {
+	return len(c.ranges) == 0
+}
+
+// AllowsAll tells whether every version allowed by the other constraint is allowed by this constraint.
+func (c *Constraint) AllowsAll(other *Constraint) bool {
+	for _, o := range other.ranges {
+		contained := false
+		for _, r := range c.ranges {
+			if r.allowsAll(o) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowsAny tells whether this and the other constraint allow at least one common version.
+func (c *Constraint) AllowsAny(other *Constraint) bool {
+	return !c.Intersect(other).IsEmpty()
+}
+
+// Intersect returns the constraint allowing the versions, which are allowed by both constraints.
+func (c *Constraint) Intersect(other *Constraint) *Constraint {
+	var ranges []versionRange
+	for _, r := range c.ranges {
+		for _, o := range other.ranges {
+			if intersection, ok := r.intersect(o); ok {
+				ranges = append(ranges, intersection)
+			}
+		}
+	}
+	return &Constraint{ranges: mergeRanges(ranges)}
+}
+
+// Union returns the constraint allowing the versions, which are allowed by any of the constraints.
+func (c *Constraint) Union(other *Constraint) *Constraint {
+	ranges := append(append([]versionRange{}, c.ranges...), other.ranges...)
+	return &Constraint{ranges: mergeRanges(ranges)}
+}
+
+// String returns the parsed text of the constraint, or for computed constraints the pub representation of its ranges.
+func (c *Constraint) String() string {
+	if c.text != "" {
+		return c.text
+	}
+	if c.IsEmpty() {
+		return "<empty>"
+	}
+
+	var ranges []string
+	for _, r := range c.ranges {
+		ranges = append(ranges, r.String())
+	}
+	return strings.Join(ranges, " or ")
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (c *Constraint) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (r versionRange) allows(version *semver.Version) bool {
+	if r.min != nil {
+		cmp := compareVersions(version, r.min)
+		if cmp < 0 || (cmp == 0 && !r.includeMin) {
+			return false
+		}
+	}
+	if r.max != nil {
+		cmp := compareVersions(version, r.max)
+		if cmp > 0 || (cmp == 0 && !r.includeMax) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r versionRange) allowsAll(other versionRange) bool {
+	return !allowsLower(other, r) && !allowsHigher(other, r)
+}
+
+func (r versionRange) intersect(other versionRange) (versionRange, bool) {
+	lower, upper := r, r
+	if allowsLower(r, other) {
+		lower = other
+	}
+	if allowsHigher(r, other) {
+		upper = other
+	}
+
+	intersection := versionRange{min: lower.min, includeMin: lower.includeMin, max: upper.max, includeMax: upper.includeMax}
+	if intersection.min != nil && intersection.max != nil {
+		switch cmp := compareVersions(intersection.min, intersection.max); {
+		case cmp > 0:
+			return versionRange{}, false
+		case cmp == 0:
+			return intersection, intersection.includeMin && intersection.includeMax
+		}
+	}
+	return intersection, true
+}
+
+func (r versionRange) String() string {
+	if r.min != nil && r.max != nil && r.includeMin && r.includeMax && compareVersions(r.min, r.max) == 0 {
+		return r.min.String()
+	}
+
+	var b strings.Builder
+	if r.min != nil {
+		if r.includeMin {
+			b.WriteString(">=")
+		} else {
+			b.WriteString(">")
+		}
+		b.WriteString(r.min.String())
+	}
+	if r.max != nil {
+		if r.min != nil {
+			b.WriteString(" ")
+		}
+		if r.includeMax {
+			b.WriteString("<=" + r.max.String())
+		} else if isFirstPreRelease(r.max) {
+			// <2.0.0 parses as <2.0.0-0
+			b.WriteString(fmt.Sprintf("<%d.%d.%d", r.max.Major(), r.max.Minor(), r.max.Patch()))
+		} else {
+			b.WriteString("<" + r.max.String())
+			minIsPreReleaseOfMax := r.min != nil && r.min.Prerelease() != "" && equalsWithoutPreRelease(r.min, r.max)
+			if r.max.Prerelease() == "" && r.max.Metadata() == "" && !minIsPreReleaseOfMax {
+				// the range allows the pre-releases of max, which <max wouldn't
+				b.WriteString("-∞")
+			}
+		}
+	}
+	if r.min == nil && r.max == nil {
+		b.WriteString("any")
+	}
+	return b.String()
+}
+
+// mergeRanges sorts the ranges and merges the overlapping and adjacent ones.
+func mergeRanges(ranges []versionRange) []versionRange {
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return compareRanges(ranges[i], ranges[j]) < 0
+	})
+
+	var merged []versionRange
+	for _, r := range ranges {
+		if len(merged) == 0 {
+			merged = append(merged, r)
+			continue
+		}
+
+		last := merged[len(merged)-1]
+		if strictlyLower(last, r) && !areAdjacent(last, r) {
+			merged = append(merged, r)
+			continue
+		}
+
+		if allowsHigher(r, last) {
+			last.max, last.includeMax = r.max, r.includeMax
+		}
+		merged[len(merged)-1] = last
+	}
+	return merged
+}
+
+// allowsLower tells whether range1 allows lower versions than range2.
+func allowsLower(range1, range2 versionRange) bool {
+	if range1.min == nil {
+		return range2.min != nil
+	}
+	if range2.min == nil {
+		return false
+	}
+
+	switch cmp := compareVersions(range1.min, range2.min); {
+	case cmp < 0:
+		return true
+	case cmp > 0:
+		return false
+	default:
+		return range1.includeMin && !range2.includeMin
+	}
+}
+
+// allowsHigher tells whether range1 allows higher versions than range2.
+func allowsHigher(range1, range2 versionRange) bool {
+	if range1.max == nil {
+		return range2.max != nil
+	}
+	if range2.max == nil {
+		return false
+	}
+
+	switch cmp := compareVersions(range1.max, range2.max); {
+	case cmp > 0:
+		return true
+	case cmp < 0:
+		return false
+	default:
+		return range1.includeMax && !range2.includeMax
+	}
+}
+
+// strictlyLower tells whether every version of range1 is lower than the versions of range2.
+func strictlyLower(range1, range2 versionRange) bool {
+	if range1.max == nil || range2.min == nil {
+		return false
+	}
+
+	switch cmp := compareVersions(range1.max, range2.min); {
+	case cmp < 0:
+		return true
+	case cmp > 0:
+		return false
+	default:
+		return !range1.includeMax || !range2.includeMin
+	}
+}
+
+// areAdjacent tells whether range1 ends where range2 starts, and the two ranges together allow their common bound exactly once.
+func areAdjacent(range1, range2 versionRange) bool {
+	if range1.max == nil || range2.min == nil || compareVersions(range1.max, range2.min) != 0 {
+		return false
+	}
+	return range1.includeMax != range2.includeMin
+}
+
+func compareRanges(range1, range2 versionRange) int {
+	if range1.min == nil || range2.min == nil {
+		if range1.min == nil && range2.min == nil {
+			return compareMax(range1, range2)
+		}
+		if range1.min == nil {
+			return -1
+		}
+		return 1
+	}
+
+	if cmp := compareVersions(range1.min, range2.min); cmp != 0 {
+		return cmp
+	}
+	if range1.includeMin != range2.includeMin {
+		if range1.includeMin {
+			return -1
+		}
+		return 1
+	}
+	return compareMax(range1, range2)
+}
+
+func compareMax(range1, range2 versionRange) int {
+	if range1.max == nil || range2.max == nil {
+		if range1.max == nil && range2.max == nil {
+			return 0
+		}
+		if range1.max == nil {
+			return 1
+		}
+		return -1
+	}
+
+	if cmp := compareVersions(range1.max, range2.max); cmp != 0 {
+		return cmp
+	}
+	if range1.includeMax != range2.includeMax {
+		if range1.includeMax {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+/*
+compareVersions orders the versions like pub: pre-releases come before the release,
+and unlike semantic versioning, build metadata takes part in the ordering, builds come after the version without a build.
+*/
+func compareVersions(v1, v2 *semver.Version) int {
+	for _, cmp := range []int{compareUint(v1.Major(), v2.Major()), compareUint(v1.Minor(), v2.Minor()), compareUint(v1.Patch(), v2.Patch())} {
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	switch {
+	case v1.Prerelease() == "" && v2.Prerelease() != "":
+		return 1
+	case v1.Prerelease() != "" && v2.Prerelease() == "":
+		return -1
+	}
+	if cmp := compareIdentifiers(v1.Prerelease(), v2.Prerelease()); cmp != 0 {
+		return cmp
+	}
+
+	switch {
+	case v1.Metadata() == "" && v2.Metadata() != "":
+		return -1
+	case v1.Metadata() != "" && v2.Metadata() == "":
+		return 1
+	}
+	return compareIdentifiers(v1.Metadata(), v2.Metadata())
+}
+
+// compareIdentifiers compares dot separated pre-release or build identifiers, numeric identifiers come before alphanumeric ones.
+func compareIdentifiers(s1, s2 string) int {
+	parts1, parts2 := strings.Split(s1, "."), strings.Split(s2, ".")
+	for i := 0; i < len(parts1) && i < len(parts2); i++ {
+		n1, err1 := strconv.ParseUint(parts1[i], 10, 64)
+		n2, err2 := strconv.ParseUint(parts2[i], 10, 64)
+
+		var cmp int
+		switch {
+		case err1 == nil && err2 == nil:
+			cmp = compareUint(n1, n2)
+		case err1 == nil:
+			cmp = -1
+		case err2 == nil:
+			cmp = 1
+		default:
+			cmp = strings.Compare(parts1[i], parts2[i])
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return compareUint(uint64(len(parts1)), uint64(len(parts2)))
+}
+
+func compareUint(n1, n2 uint64) int {
+	switch {
+	case n1 < n2:
+		return -1
+	case n1 > n2:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func equalsWithoutPreRelease(v1, v2 *semver.Version) bool {
+	return v1.Major() == v2.Major() && v1.Minor() == v2.Minor() && v1.Patch() == v2.Patch()
+}
+
+func firstPreRelease(version *semver.Version) *semver.Version {
+	return semver.New(version.Major(), version.Minor(), version.Patch(), "0", "")
+}
+
+func isFirstPreRelease(version *semver.Version) bool {
+	return version.Prerelease() == "0" && version.Metadata() == ""
+}
+
+// nextBreaking returns the first version, which is not backward compatible with the given version:
+// the next major version, or for 0.x versions the next minor version.
+func nextBreaking(version *semver.Version) *semver.Version {
+	if version.Major() == 0 {
+		return semver.New(0, version.Minor()+1, 0, "", "")
+	}
+	return semver.New(version.Major()+1, 0, 0, "", "")
+}
@@ -0,0 +1,356 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseConstraint(t *testing.T, text string) *Constraint {
+	c, err := ParseConstraint(text)
+	require.NoError(t, err)
+	return c
+}
+
+func Test_compareVersions(t *testing.T) {
+	// pub_semver's version ordering test
+	versions := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0-rc.1+build.1",
+		"1.0.0",
+		"1.0.0+0.3.7",
+		"1.3.7+build",
+		"1.3.7+build.2.b8f12d7",
+		"1.3.7+build.11.e0f985a",
+		"2.0.0",
+		"2.1.0",
+		"2.2.0",
+		"2.11.0",
+		"2.11.1",
+	}
+	for i := range versions {
+		for j := range versions {
+			got := compareVersions(semver.MustParse(versions[i]), semver.MustParse(versions[j]))
+			switch {
+			case i < j:
+				require.Equal(t, -1, got, "%s < %s", versions[i], versions[j])
+			case i > j:
+				require.Equal(t, 1, got, "%s > %s", versions[i], versions[j])
+			default:
+				require.Equal(t, 0, got, "%s == %s", versions[i], versions[j])
+			}
+		}
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		allows     []string
+		disallows  []string
+	}{
+		{
+			constraint: "any",
+			allows:     []string{"0.0.0", "1.2.3", "1.2.3-dev", "1.2.3+build"},
+		},
+		{
+			constraint: "1.2.3-pre",
+			allows:     []string{"1.2.3-pre"},
+			disallows:  []string{"1.2.3", "1.2.3-pre+build", "1.2.2"},
+		},
+		{
+			constraint: "1.2.3",
+			allows:     []string{"1.2.3"},
+			disallows:  []string{"1.2.3+build", "1.2.3-dev", "1.2.4"},
+		},
+		{
+			constraint: ">1.0.0",
+			allows:     []string{"1.0.1", "1.1.0", "1.0.0+build", "1.0.1-dev"},
+			disallows:  []string{"0.9.9", "1.0.0", "1.0.0-dev"},
+		},
+		{
+			constraint: "<1.2.3",
+			allows:     []string{"0.0.0", "1.2.2", "1.2.2-dev", "1.0.0-dev"},
+			disallows:  []string{"1.2.3", "1.2.3-pre", "1.2.3-0", "2.0.0"},
+		},
+		{
+			constraint: ">=1.2.3",
+			allows:     []string{"1.2.3", "1.2.3+build", "1.2.4-dev", "2.0.0"},
+			disallows:  []string{"1.2.2", "1.2.3-dev", "0.0.0"},
+		},
+		{
+			constraint: "<=1.2.3",
+			allows:     []string{"0.0.0", "1.2.2", "1.2.3-pre", "1.2.3"},
+			disallows:  []string{"1.2.3+build", "1.2.4-dev", "2.0.0"},
+		},
+		{
+			constraint: ">1.0.0 <=1.2.3",
+			allows:     []string{"1.0.1", "1.1.0", "1.2.3"},
+			disallows:  []string{"0.0.0", "1.0.0", "1.2.4", "2.0.0"},
+		},
+		{
+			constraint: ">1.0.0<=1.2.3",
+			allows:     []string{"1.0.1", "1.2.3"},
+			disallows:  []string{"1.0.0", "1.2.4"},
+		},
+		{
+			constraint: " > 1.0.0  <= 1.2.3 ",
+			allows:     []string{"1.0.1", "1.2.3"},
+			disallows:  []string{"1.0.0", "1.2.4"},
+		},
+		{
+			constraint: ">1.0.0 >1.2.3 <1.3.0 <2.0.0",
+			allows:     []string{"1.2.4", "1.2.9"},
+			disallows:  []string{"1.2.3", "1.3.0", "1.3.0-dev", "1.9.9"},
+		},
+		{
+			constraint: ">=1.0.0 >1.0.0",
+			disallows:  []string{"1.0.0"},
+		},
+		{
+			constraint: ">=1.2.3 <=1.2.3",
+			allows:     []string{"1.2.3"},
+			disallows:  []string{"1.2.3-dev", "1.2.3+build", "1.2.4"},
+		},
+		{
+			constraint: ">=1.2.3 <1.2.3",
+			disallows:  []string{"1.2.2", "1.2.3", "1.2.4"},
+		},
+		{
+			constraint: ">2.0.0 <1.0.0",
+			disallows:  []string{"0.9.9", "1.5.0", "2.0.1"},
+		},
+		{
+			constraint: "^1.2.3",
+			allows:     []string{"1.2.3", "1.2.4-dev", "1.9.9", "1.9.9+build"},
+			disallows:  []string{"1.2.2", "1.2.3-dev", "2.0.0-dev", "2.0.0", "3.0.0"},
+		},
+		{
+			constraint: "^0.7.2",
+			allows:     []string{"0.7.2", "0.7.9"},
+			disallows:  []string{"0.7.1", "0.8.0-dev", "0.8.0", "1.0.0"},
+		},
+		{
+			constraint: "^0.0.3",
+			allows:     []string{"0.0.3", "0.0.9"},
+			disallows:  []string{"0.0.2", "0.1.0-dev", "0.1.0"},
+		},
+		{
+			constraint: " ^ 1.2.3 ",
+			allows:     []string{"1.2.3", "1.9.9"},
+			disallows:  []string{"2.0.0"},
+		},
+		{
+			constraint: ">=2.0.0-dev <2.0.0",
+			allows:     []string{"2.0.0-dev", "2.0.0-pre"},
+			disallows:  []string{"1.9.9", "2.0.0"},
+		},
+		{
+			constraint: "<2.0.0-beta",
+			allows:     []string{"2.0.0-alpha", "1.9.9"},
+			disallows:  []string{"2.0.0-beta", "2.0.0"},
+		},
+		{
+			constraint: "<2.0.0+1",
+			allows:     []string{"2.0.0-dev", "2.0.0"},
+			disallows:  []string{"2.0.0+1", "2.0.1"},
+		},
+		{
+			// Dart 3 beta SDKs
+			constraint: ">=3.0.0-0 <4.0.0",
+			allows:     []string{"3.0.0-417.1.beta", "3.0.0", "3.5.0-180.3.beta"},
+			disallows:  []string{"2.19.6", "4.0.0-0", "4.0.0-1.0.dev"},
+		},
+		{
+			// Flutter pre-releases
+			constraint: ">=3.13.0 <3.16.0",
+			allows:     []string{"3.13.0", "3.14.0-0.2.pre", "3.15.9"},
+			disallows:  []string{"3.13.0-0.1.pre", "3.16.0-0.5.pre", "3.16.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c := mustParseConstraint(t, tt.constraint)
+			for _, v := range tt.allows {
+				require.True(t, c.Check(semver.MustParse(v)), "%s should allow %s", tt.constraint, v)
+			}
+			for _, v := range tt.disallows {
+				require.False(t, c.Check(semver.MustParse(v)), "%s should not allow %s", tt.constraint, v)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Errors(t *testing.T) {
+	tests := []struct {
+		constraint string
+		wantErr    string
+	}{
+		{constraint: "", wantErr: "cannot parse an empty string"},
+		{constraint: "   ", wantErr: "cannot parse an empty string"},
+		{constraint: ">", wantErr: `expected version number after ">" in ">", got ""`},
+		{constraint: ">=abc", wantErr: `expected version number after ">=" in ">=abc", got "abc"`},
+		{constraint: "blah", wantErr: `could not parse version "blah", unknown text at "blah"`},
+		{constraint: "1.0.0 blah", wantErr: `could not parse version "1.0.0 blah", unknown text at "blah"`},
+		{constraint: "1.2", wantErr: `could not parse version "1.2", unknown text at "1.2"`},
+		{constraint: "=1.2.3", wantErr: `could not parse version "=1.2.3", unknown text at "=1.2.3"`},
+		{constraint: "^", wantErr: `expected version number after "^" in "^", got ""`},
+		{constraint: "^1.0", wantErr: `expected version number after "^" in "^1.0", got "1.0"`},
+		{constraint: "^1.0.0 <2.0.0", wantErr: `cannot include other constraints with "^" constraint in "^1.0.0 <2.0.0"`},
+		{constraint: ">=1.0.0 ^2.0.0", wantErr: `could not parse version ">=1.0.0 ^2.0.0", unknown text at "^2.0.0"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, err := ParseConstraint(tt.constraint)
+			require.EqualError(t, err, tt.wantErr)
+			require.Nil(t, got)
+		})
+	}
+}
+
+func TestConstraint_Intersect(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraint1 string
+		constraint2 string
+		want        string
+	}{
+		{name: "Overlapping ranges", constraint1: ">=1.0.0 <2.0.0", constraint2: ">=1.5.0 <3.0.0", want: ">=1.5.0 <2.0.0"},
+		{name: "Nested ranges", constraint1: "^1.0.0", constraint2: ">=1.2.0 <1.3.0", want: ">=1.2.0 <1.3.0"},
+		{name: "Disjoint ranges", constraint1: "<1.0.0", constraint2: ">=2.0.0", want: "<empty>"},
+		{name: "Ranges touching at an inclusive bound", constraint1: ">=1.0.0 <=2.0.0", constraint2: ">=2.0.0 <3.0.0", want: "2.0.0"},
+		{name: "Ranges touching at an exclusive bound", constraint1: ">=1.0.0 <2.0.0", constraint2: ">=2.0.0 <3.0.0", want: "<empty>"},
+		{name: "Any", constraint1: "any", constraint2: "^3.22.0", want: ">=3.22.0 <4.0.0"},
+		{name: "Version in range", constraint1: "^1.0.0", constraint2: "1.5.0", want: "1.5.0"},
+		{name: "Version out of range", constraint1: "^1.0.0", constraint2: "2.5.0", want: "<empty>"},
+		{name: "Pre-release minimum", constraint1: ">=3.0.0-0 <4.0.0", constraint2: "<=3.0.0", want: ">=3.0.0-0 <=3.0.0"},
+		{name: "Exclusive max excludes the pre-release minimum", constraint1: ">=3.0.0-0 <4.0.0", constraint2: "<3.0.0", want: "<empty>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c1, c2 := mustParseConstraint(t, tt.constraint1), mustParseConstraint(t, tt.constraint2)
+			require.Equal(t, tt.want, c1.Intersect(c2).String())
+			require.Equal(t, tt.want, c2.Intersect(c1).String())
+		})
+	}
+}
+
+func TestConstraint_Union(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraint1 string
+		constraint2 string
+		want        string
+	}{
+		{name: "Overlapping ranges", constraint1: ">=1.0.0 <2.0.0", constraint2: ">=1.5.0 <3.0.0", want: ">=1.0.0 <3.0.0"},
+		{name: "Nested ranges", constraint1: "^1.0.0", constraint2: ">=1.2.0 <1.3.0", want: ">=1.0.0 <2.0.0"},
+		{name: "Disjoint ranges", constraint1: ">=2.0.0", constraint2: "<1.0.0", want: "<1.0.0 or >=2.0.0"},
+		{name: "Adjacent ranges", constraint1: ">=1.0.0 <=2.0.0", constraint2: ">2.0.0 <3.0.0", want: ">=1.0.0 <3.0.0"},
+		{name: "Pre-releases of the exclusive bound separate the ranges", constraint1: ">=1.0.0 <2.0.0", constraint2: ">=2.0.0 <3.0.0", want: ">=1.0.0 <2.0.0 or >=2.0.0 <3.0.0"},
+		{name: "Any", constraint1: "any", constraint2: "^3.22.0", want: "any"},
+		{name: "Versions", constraint1: "2.0.0", constraint2: "1.0.0", want: "1.0.0 or 2.0.0"},
+		{name: "Version in range", constraint1: "^1.0.0", constraint2: "1.5.0", want: ">=1.0.0 <2.0.0"},
+		{name: "Version at the exclusive bound", constraint1: ">1.0.0 <2.0.0", constraint2: "1.0.0", want: ">=1.0.0 <2.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c1, c2 := mustParseConstraint(t, tt.constraint1), mustParseConstraint(t, tt.constraint2)
+			require.Equal(t, tt.want, c1.Union(c2).String())
+			require.Equal(t, tt.want, c2.Union(c1).String())
+		})
+	}
+}
+
+func TestConstraint_Allows(t *testing.T) {
+	tests := []struct {
+		name          string
+		constraint1   string
+		constraint2   string
+		wantAllowsAll bool
+		wantAllowsAny bool
+	}{
+		{name: "Any allows a range", constraint1: "any", constraint2: "^1.0.0", wantAllowsAll: true, wantAllowsAny: true},
+		{name: "Range doesn't allow any", constraint1: "^1.0.0", constraint2: "any", wantAllowsAny: true},
+		{name: "Range allows a nested range", constraint1: "^1.0.0", constraint2: ">=1.2.0 <1.3.0", wantAllowsAll: true, wantAllowsAny: true},
+		{name: "Range allows a version in it", constraint1: "^1.0.0", constraint2: "1.5.0", wantAllowsAll: true, wantAllowsAny: true},
+		{name: "Overlapping ranges", constraint1: ">=1.0.0 <2.0.0", constraint2: ">=1.5.0 <3.0.0", wantAllowsAny: true},
+		{name: "Disjoint ranges", constraint1: "<1.0.0", constraint2: ">=2.0.0"},
+		{name: "Exclusive bounds", constraint1: ">1.0.0", constraint2: ">=1.0.0", wantAllowsAny: true},
+		{name: "Exclusive max excludes its pre-releases", constraint1: "<2.0.0", constraint2: ">=2.0.0-dev <=2.0.0-dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c1, c2 := mustParseConstraint(t, tt.constraint1), mustParseConstraint(t, tt.constraint2)
+			require.Equal(t, tt.wantAllowsAll, c1.AllowsAll(c2))
+			require.Equal(t, tt.wantAllowsAny, c1.AllowsAny(c2))
+			require.Equal(t, tt.wantAllowsAny, c2.AllowsAny(c1))
+		})
+	}
+
+	require.True(t, EmptyConstraint().AllowsAll(EmptyConstraint()))
+	require.True(t, mustParseConstraint(t, "^1.0.0").AllowsAll(EmptyConstraint()))
+	require.False(t, EmptyConstraint().AllowsAny(AnyConstraint()))
+	require.True(t, AnyConstraint().IsAny())
+	require.True(t, mustParseConstraint(t, ">=2.0.0 <1.0.0").IsEmpty())
+}
+
+func TestNewVersionRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		min        string
+		includeMin bool
+		max        string
+		includeMax bool
+		want       string
+	}{
+		{name: "Exclusive max excludes its pre-releases", min: "1.0.0", includeMin: true, max: "2.0.0", want: ">=1.0.0 <2.0.0"},
+		{name: "Pre-release max", min: "1.0.0", includeMin: true, max: "2.0.0-dev", want: ">=1.0.0 <2.0.0-dev"},
+		{name: "Min is a pre-release of max", min: "2.0.0-dev", includeMin: true, max: "2.0.0", want: ">=2.0.0-dev <2.0.0"},
+		{name: "Inclusive max", max: "2.0.0", includeMax: true, want: "<=2.0.0"},
+		{name: "No bounds", want: "any"},
+		{name: "Same min and max", min: "1.0.0", includeMin: true, max: "1.0.0", includeMax: true, want: "1.0.0"},
+		{name: "Min above max", min: "2.0.0", max: "1.0.0", want: "<empty>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var min, max *semver.Version
+			if tt.min != "" {
+				min = semver.MustParse(tt.min)
+			}
+			if tt.max != "" {
+				max = semver.MustParse(tt.max)
+			}
+			require.Equal(t, tt.want, NewVersionRange(min, tt.includeMin, max, tt.includeMax).String())
+		})
+	}
+}
+
+func TestConstraint_MinVersion(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{constraint: "<3.0.0 >=2.10.0", want: "2.10.0"},
+		{constraint: "^3.22.0", want: "3.22.0"},
+		{constraint: "1.5.0", want: "1.5.0"},
+		{constraint: "<3.0.0"},
+		{constraint: "any"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got := mustParseConstraint(t, tt.constraint).MinVersion()
+			if tt.want == "" {
+				require.Nil(t, got)
+				return
+			}
+			require.Equal(t, tt.want, got.String())
+		})
+	}
+
+	require.Nil(t, EmptyConstraint().MinVersion())
+}
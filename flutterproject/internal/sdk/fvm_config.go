@@ -1,15 +1,26 @@
 package sdk
 
 import (
+	"bufio"
 	"encoding/json"
+	"fmt"
 	"io"
 	"path/filepath"
+	"sort"
 	"strings"
+)
 
-	"github.com/Masterminds/semver/v3"
+const (
+	// FVMRCRelPath is the project config of FVM 3.
+	FVMRCRelPath = ".fvmrc"
+	// FVMConfigRelPath is the project config of FVM 2, FVM 3 keeps writing it for compatibility.
+	FVMConfigRelPath = ".fvm/fvm_config.json"
+	// FVMVersionRelPath is the state file of FVM 3, it contains the version of the SDK linked into the project.
+	FVMVersionRelPath = ".fvm/version"
 )
 
-const fvmConfigRelPath = ".fvm/fvm_config.json"
+// FVMConfigRelPaths lists the FVM config files in FVM's precedence order.
+var FVMConfigRelPaths = []string{FVMRCRelPath, FVMConfigRelPath, FVMVersionRelPath}
 
 type FVMVersionReader struct {
 	fileOpener FileOpener
@@ -21,51 +32,141 @@ func NewFVMVersionReader(fileOpener FileOpener) FVMVersionReader {
 	}
 }
 
-func (r FVMVersionReader) ReadSDKVersion(projectRootDir string) (*semver.Version, string, error) {
-	fvmConfigPth := filepath.Join(projectRootDir, fvmConfigRelPath)
-	f, err := r.fileOpener.OpenReaderIfExists(fvmConfigPth)
-	if err != nil {
-		return nil, "", err
+// ReadSDKVersion returns the Flutter SDK pinned by FVM and the project relative path of the config file it was read from.
+// If flavor is not empty, the SDK pinned for the given FVM flavor is returned.
+func (r FVMVersionReader) ReadSDKVersion(projectRootDir, flavor string) (*FlutterPin, string, error) {
+	for _, configRelPath := range FVMConfigRelPaths {
+		if flavor != "" && configRelPath == FVMVersionRelPath {
+			// .fvm/version only contains the currently linked SDK version
+			continue
+		}
+
+		config, err := r.readConfig(projectRootDir, configRelPath)
+		if err != nil {
+			return nil, "", err
+		}
+		if config == nil {
+			continue
+		}
+
+		versionStr := config.Flutter
+		if flavor != "" {
+			flavorVersion, ok := config.Flavors[flavor]
+			if !ok {
+				return nil, "", fmt.Errorf("fvm flavor not found in %s: %s (available flavors: %s)", configRelPath, flavor, strings.Join(config.flavorNames(), ", "))
+			}
+			versionStr = flavorVersion
+		}
+
+		versionStr, channel := splitFVMVersion(versionStr)
+		if versionStr == "" {
+			continue
+		}
+
+		pin, err := parseFlutterPin(versionStr, channel)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return pin, configRelPath, nil
 	}
 
-	if f == nil {
-		return nil, "", nil
+	if flavor != "" {
+		return nil, "", fmt.Errorf("fvm flavor not found: %s", flavor)
+	}
+
+	return nil, "", nil
+}
+
+// ReadFlavors returns the FVM flavors (flavor name - pinned version pairs) and the project relative path of the config file they were read from.
+func (r FVMVersionReader) ReadFlavors(projectRootDir string) (map[string]string, string, error) {
+	for _, configRelPath := range []string{FVMRCRelPath, FVMConfigRelPath} {
+		config, err := r.readConfig(projectRootDir, configRelPath)
+		if err != nil {
+			return nil, "", err
+		}
+		if config != nil {
+			return config.Flavors, configRelPath, nil
+		}
 	}
 
-	versionStr, channel, err := parseFVMFlutterVersion(f)
+	return nil, "", nil
+}
+
+func (r FVMVersionReader) readConfig(projectRootDir, configRelPath string) (*fvmConfig, error) {
+	fvmConfigPth := filepath.Join(projectRootDir, configRelPath)
+	f, err := r.fileOpener.OpenReaderIfExists(fvmConfigPth)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
-	if versionStr == "" {
-		return nil, "", nil
+
+	if f == nil {
+		return nil, nil
 	}
 
-	version, err := semver.NewVersion(versionStr)
-	if err != nil {
-		return nil, "", err
+	if configRelPath == FVMVersionRelPath {
+		versionStr, err := readFVMVersionFile(f)
+		if err != nil {
+			return nil, err
+		}
+		return &fvmConfig{Flutter: versionStr}, nil
 	}
 
-	return version, channel, nil
+	return parseFVMConfig(f)
+}
+
+type fvmConfig struct {
+	Flutter           string            `json:"flutter"`
+	FlutterSdkVersion string            `json:"flutterSdkVersion"`
+	Flavors           map[string]string `json:"flavors"`
 }
 
-func parseFVMFlutterVersion(fvmConfigReader io.Reader) (string, string, error) {
-	type fvmConfig struct {
-		FlutterSdkVersion string `json:"flutterSdkVersion"`
+func (c fvmConfig) flavorNames() []string {
+	var names []string
+	for name := range c.Flavors {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
+}
 
+// parseFVMConfig parses both the FVM 3 (.fvmrc) and the FVM 2 (.fvm/fvm_config.json) config formats.
+func parseFVMConfig(fvmConfigReader io.Reader) (*fvmConfig, error) {
 	var config fvmConfig
 	d := json.NewDecoder(fvmConfigReader)
 	if err := d.Decode(&config); err != nil {
-		return "", "", err
+		return nil, err
+	}
+
+	if config.Flutter == "" {
+		config.Flutter = config.FlutterSdkVersion
 	}
 
-	version := config.FlutterSdkVersion
+	return &config, nil
+}
+
+func readFVMVersionFile(fvmVersionReader io.Reader) (string, error) {
+	scanner := bufio.NewScanner(fvmVersionReader)
+	versionStr := ""
+	if scanner.Scan() {
+		versionStr = strings.TrimSpace(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return versionStr, nil
+}
+
+// splitFVMVersion splits FVM's version@channel format.
+func splitFVMVersion(versionStr string) (string, string) {
+	version := versionStr
 	channel := ""
-	s := strings.Split(config.FlutterSdkVersion, "@")
+	s := strings.Split(versionStr, "@")
 	if len(s) > 1 {
 		version = s[0]
 		channel = strings.Join(s[1:], "@")
 	}
 
-	return version, channel, nil
+	return version, channel
 }
@@ -1,53 +1,211 @@
 package sdk
 
 import (
-	"io"
 	"strings"
 	"testing"
 
 	"github.com/bitrise-io/go-flutter/flutterproject/internal/testassets"
+	"github.com/bitrise-io/go-flutter/mocks"
 	"github.com/stretchr/testify/require"
 )
 
-func Test_parseFVMFlutterVersion(t *testing.T) {
+func TestFVMVersionReader_readConfig(t *testing.T) {
 	tests := []struct {
-		name            string
-		fvmConfigReader io.Reader
-		wantFlutterSDK  string
-		wantChannel     string
-		wantErr         string
+		name           string
+		configRelPath  string
+		config         string
+		wantFlutterSDK string
+		wantChannel    string
+		wantErr        string
 	}{
 		{
-			name:            "Real fvm_config.json",
-			fvmConfigReader: strings.NewReader(testassets.FVMConfigJSON),
-			wantFlutterSDK:  "3.7.12",
+			name:           "Real fvm_config.json",
+			configRelPath:  FVMConfigRelPath,
+			config:         testassets.FVMConfigJSON,
+			wantFlutterSDK: "3.7.12",
 		},
 		{
-			name: "Real fvm_config.json with channel",
-			fvmConfigReader: strings.NewReader(`{
+			name:          "Real fvm_config.json with channel",
+			configRelPath: FVMConfigRelPath,
+			config: `{
   "flutterSdkVersion": "2.2.2@beta",
   "flavors": {}
-}`),
+}`,
 			wantFlutterSDK: "2.2.2",
 			wantChannel:    "beta",
 		},
 		{
-			name:            "Empty fvm_config.json",
-			fvmConfigReader: strings.NewReader(""),
-			wantErr:         "EOF",
+			name:          "FVM 3 .fvmrc",
+			configRelPath: FVMRCRelPath,
+			config: `{
+  "flutter": "3.19.6@stable",
+  "flavors": {
+    "production": "3.16.9"
+  }
+}`,
+			wantFlutterSDK: "3.19.6",
+			wantChannel:    "stable",
+		},
+		{
+			name:           ".fvm/version",
+			configRelPath:  FVMVersionRelPath,
+			config:         "3.22.2\n",
+			wantFlutterSDK: "3.22.2",
+		},
+		{
+			name:          "Empty fvm_config.json",
+			configRelPath: FVMConfigRelPath,
+			config:        "",
+			wantErr:       "EOF",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotFlutterSDK, gotChannel, err := parseFVMFlutterVersion(tt.fvmConfigReader)
+			fileOpener := new(mocks.FileManager)
+			fileOpener.On("OpenReaderIfExists", tt.configRelPath).Return(strings.NewReader(tt.config), nil)
+
+			config, err := NewFVMVersionReader(fileOpener).readConfig("", tt.configRelPath)
 			if tt.wantErr != "" {
 				require.EqualError(t, err, tt.wantErr)
-				require.Empty(t, gotFlutterSDK)
+				require.Nil(t, config)
 			} else {
 				require.NoError(t, err)
+				gotFlutterSDK, gotChannel := splitFVMVersion(config.Flutter)
 				require.Equal(t, tt.wantFlutterSDK, gotFlutterSDK)
 				require.Equal(t, tt.wantChannel, gotChannel)
 			}
 		})
 	}
 }
+
+func TestFVMVersionReader_ReadSDKVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		files          map[string]string
+		wantFlutterSDK string
+		wantChannel    string
+		wantCommit     string
+		wantConfigPath string
+	}{
+		{
+			name: ".fvmrc takes precedence",
+			files: map[string]string{
+				".fvmrc":               `{"flutter": "3.19.6"}`,
+				".fvm/fvm_config.json": testassets.FVMConfigJSON,
+				".fvm/version":         "3.7.12",
+			},
+			wantFlutterSDK: "3.19.6",
+			wantConfigPath: ".fvmrc",
+		},
+		{
+			name: "Legacy fvm_config.json",
+			files: map[string]string{
+				".fvm/fvm_config.json": testassets.FVMConfigJSON,
+				".fvm/version":         "3.19.6",
+			},
+			wantFlutterSDK: "3.7.12",
+			wantConfigPath: ".fvm/fvm_config.json",
+		},
+		{
+			name: ".fvm/version",
+			files: map[string]string{
+				".fvm/version": "3.19.6",
+			},
+			wantFlutterSDK: "3.19.6",
+			wantConfigPath: ".fvm/version",
+		},
+		{
+			name: "Channel head",
+			files: map[string]string{
+				".fvmrc": `{"flutter": "beta"}`,
+			},
+			wantChannel:    "beta",
+			wantConfigPath: ".fvmrc",
+		},
+		{
+			name: "Commit",
+			files: map[string]string{
+				".fvmrc": `{"flutter": "761747bfc538b5af34aa0d3fac380f1bc331ec49"}`,
+			},
+			wantCommit:     "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+			wantConfigPath: ".fvmrc",
+		},
+		{
+			name:  "No FVM config",
+			files: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileOpener := new(mocks.FileManager)
+			for _, pth := range FVMConfigRelPaths {
+				if content, ok := tt.files[pth]; ok {
+					fileOpener.On("OpenReaderIfExists", pth).Return(strings.NewReader(content), nil)
+				} else {
+					fileOpener.On("OpenReaderIfExists", pth).Return(nil, nil)
+				}
+			}
+
+			gotPin, gotConfigPath, err := NewFVMVersionReader(fileOpener).ReadSDKVersion("", "")
+			require.NoError(t, err)
+			if tt.wantFlutterSDK == "" && tt.wantChannel == "" && tt.wantCommit == "" {
+				require.Nil(t, gotPin)
+			} else {
+				if tt.wantFlutterSDK == "" {
+					require.Nil(t, gotPin.Version)
+				} else {
+					require.Equal(t, tt.wantFlutterSDK, gotPin.Version.String())
+				}
+				require.Equal(t, tt.wantChannel, gotPin.Channel)
+				require.Equal(t, tt.wantCommit, gotPin.Commit)
+			}
+			require.Equal(t, tt.wantConfigPath, gotConfigPath)
+		})
+	}
+}
+
+func TestFVMVersionReader_ReadSDKVersion_Flavor(t *testing.T) {
+	fvmrc := `{
+  "flutter": "3.19.6",
+  "flavors": {
+    "production": "3.16.9@stable",
+    "beta": "3.22.0"
+  }
+}`
+
+	tests := []struct {
+		name           string
+		flavor         string
+		wantFlutterSDK string
+		wantChannel    string
+		wantErr        string
+	}{
+		{
+			name:           "Flavor version",
+			flavor:         "production",
+			wantFlutterSDK: "3.16.9",
+			wantChannel:    "stable",
+		},
+		{
+			name:    "Unknown flavor",
+			flavor:  "staging",
+			wantErr: "fvm flavor not found in .fvmrc: staging (available flavors: beta, production)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileOpener := new(mocks.FileManager)
+			fileOpener.On("OpenReaderIfExists", ".fvmrc").Return(strings.NewReader(fvmrc), nil)
+
+			gotPin, gotConfigPath, err := NewFVMVersionReader(fileOpener).ReadSDKVersion("", tt.flavor)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantFlutterSDK, gotPin.Version.String())
+				require.Equal(t, tt.wantChannel, gotPin.Channel)
+				require.Equal(t, ".fvmrc", gotConfigPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package sdk
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	GitHubWorkflowsRelDir = ".github/workflows"
+	flutterActionName     = "subosito/flutter-action"
+)
+
+type GitHubActionsVersionReader struct {
+	fileOpener FileOpener
+	dirReader  DirEntryReader
+}
+
+func NewGitHubActionsVersionReader(fileOpener FileOpener, dirReader DirEntryReader) GitHubActionsVersionReader {
+	return GitHubActionsVersionReader{
+		fileOpener: fileOpener,
+		dirReader:  dirReader,
+	}
+}
+
+// ReadSDKVersion returns the Flutter version and channel set up by the first subosito/flutter-action step of the project's GitHub workflows
+// and the project relative path of the workflow file. Workflow files are checked in alphabetical order.
+func (r GitHubActionsVersionReader) ReadSDKVersion(projectRootDir string) (*VersionConstraint, string, string, error) {
+	workflowsDir := filepath.Join(projectRootDir, GitHubWorkflowsRelDir)
+	entries, err := r.dirReader.ReadDirEntryNames(workflowsDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, "", "", nil
+		}
+		return nil, "", "", err
+	}
+
+	sort.Strings(entries)
+	for _, entry := range entries {
+		if ext := filepath.Ext(entry); ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+
+		f, err := r.fileOpener.OpenReaderIfExists(filepath.Join(workflowsDir, entry))
+		if err != nil {
+			return nil, "", "", err
+		}
+		if f == nil {
+			continue
+		}
+
+		versionStr, channel, found, err := parseGitHubWorkflowFlutterVersion(f)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if !found {
+			continue
+		}
+
+		version, versionChannel := parseCIFlutterVersion(versionStr)
+		return version, mergeCIChannel(versionChannel, channel), path.Join(GitHubWorkflowsRelDir, entry), nil
+	}
+
+	return nil, "", "", nil
+}
+
+// parseGitHubWorkflowFlutterVersion returns the flutter-version and channel inputs of the workflow's first subosito/flutter-action step.
+// Steps reading the version from a file (flutter-version-file) are skipped, the file itself is a version source.
+func parseGitHubWorkflowFlutterVersion(workflowReader io.Reader) (string, string, bool, error) {
+	type workflowStep struct {
+		Uses string            `yaml:"uses"`
+		With map[string]string `yaml:"with"`
+	}
+	type workflowJob struct {
+		Steps []workflowStep `yaml:"steps"`
+	}
+	type workflow struct {
+		Jobs yaml.Node `yaml:"jobs"`
+	}
+
+	var config workflow
+	d := yaml.NewDecoder(workflowReader)
+	if err := d.Decode(&config); err != nil {
+		if err == io.EOF {
+			return "", "", false, nil
+		}
+		return "", "", false, err
+	}
+
+	for _, jobNode := range mappingValues(&config.Jobs) {
+		var job workflowJob
+		if err := jobNode.Decode(&job); err != nil {
+			return "", "", false, err
+		}
+
+		for _, step := range job.Steps {
+			action, _, _ := strings.Cut(step.Uses, "@")
+			if action != flutterActionName {
+				continue
+			}
+			if step.With["flutter-version-file"] != "" {
+				continue
+			}
+
+			return step.With["flutter-version"], step.With["channel"], true, nil
+		}
+	}
+
+	return "", "", false, nil
+}
+
+// mappingValues returns the values of a YAML mapping in document order.
+func mappingValues(node *yaml.Node) []*yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var values []*yaml.Node
+	for i := 1; i < len(node.Content); i += 2 {
+		values = append(values, node.Content[i])
+	}
+	return values
+}
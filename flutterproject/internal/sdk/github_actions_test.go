@@ -0,0 +1,110 @@
+package sdk
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseGitHubWorkflowFlutterVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		workflowReader io.Reader
+		wantVersion    string
+		wantChannel    string
+		wantFound      bool
+		wantErr        string
+	}{
+		{
+			name: "Version and channel",
+			workflowReader: strings.NewReader(`name: CI
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: subosito/flutter-action@v2
+        with:
+          flutter-version: 3.22.2
+          channel: stable
+          cache: true
+      - run: flutter test
+`),
+			wantVersion: "3.22.2",
+			wantChannel: "stable",
+			wantFound:   true,
+		},
+		{
+			name: "First job's version is used",
+			workflowReader: strings.NewReader(`jobs:
+  build:
+    steps:
+      - uses: subosito/flutter-action@v2
+        with:
+          flutter-version: "3.19"
+  test:
+    steps:
+      - uses: subosito/flutter-action@v2
+        with:
+          flutter-version: 3.22.2
+`),
+			wantVersion: "3.19",
+			wantFound:   true,
+		},
+		{
+			name: "Channel only",
+			workflowReader: strings.NewReader(`jobs:
+  test:
+    steps:
+      - uses: subosito/flutter-action@v2
+        with:
+          channel: beta
+`),
+			wantChannel: "beta",
+			wantFound:   true,
+		},
+		{
+			name: "Version read from file",
+			workflowReader: strings.NewReader(`jobs:
+  test:
+    steps:
+      - uses: subosito/flutter-action@v2
+        with:
+          flutter-version-file: pubspec.yaml
+`),
+		},
+		{
+			name: "No flutter-action step",
+			workflowReader: strings.NewReader(`jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v4
+`),
+		},
+		{
+			name:           "Empty workflow",
+			workflowReader: strings.NewReader(""),
+		},
+		{
+			name:           "Invalid workflow",
+			workflowReader: strings.NewReader("jobs: ["),
+			wantErr:        "yaml: line 1: did not find expected node content",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotVersion, gotChannel, gotFound, err := parseGitHubWorkflowFlutterVersion(tt.workflowReader)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantVersion, gotVersion)
+				require.Equal(t, tt.wantChannel, gotChannel)
+				require.Equal(t, tt.wantFound, gotFound)
+			}
+		})
+	}
+}
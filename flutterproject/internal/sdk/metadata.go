@@ -0,0 +1,56 @@
+package sdk
+
+import (
+	"io"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MetadataRelPath is the project metadata file written by flutter create and updated by flutter's project migrations.
+const MetadataRelPath = ".metadata"
+
+type MetadataReader struct {
+	fileOpener FileOpener
+}
+
+func NewMetadataReader(fileOpener FileOpener) MetadataReader {
+	return MetadataReader{
+		fileOpener: fileOpener,
+	}
+}
+
+// ReadRevision returns the Flutter framework revision (commit hash) and channel the project was created or last migrated with.
+func (r MetadataReader) ReadRevision(projectRootDir string) (string, string, error) {
+	metadataPth := filepath.Join(projectRootDir, MetadataRelPath)
+	f, err := r.fileOpener.OpenReaderIfExists(metadataPth)
+	if err != nil {
+		return "", "", err
+	}
+
+	if f == nil {
+		return "", "", nil
+	}
+
+	return parseMetadataRevision(f)
+}
+
+func parseMetadataRevision(metadataReader io.Reader) (string, string, error) {
+	type metadata struct {
+		Version struct {
+			Revision string `yaml:"revision"`
+			Channel  string `yaml:"channel"`
+		} `yaml:"version"`
+	}
+
+	var config metadata
+	d := yaml.NewDecoder(metadataReader)
+	if err := d.Decode(&config); err != nil {
+		if err == io.EOF {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+
+	return config.Version.Revision, config.Version.Channel, nil
+}
@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseMetadataRevision(t *testing.T) {
+	tests := []struct {
+		name           string
+		metadataReader io.Reader
+		wantRevision   string
+		wantChannel    string
+		wantErr        string
+	}{
+		{
+			name: "Metadata of an app",
+			metadataReader: strings.NewReader(`# This file tracks properties of this Flutter project.
+# Used by Flutter tool to assess capabilities and perform upgrades etc.
+#
+# This file should be version controlled and should not be manually edited.
+
+version:
+  revision: "a14f74ff3a1cbd521163c5f03d68113d50af93d3"
+  channel: "stable"
+
+project_type: app
+`),
+			wantRevision: "a14f74ff3a1cbd521163c5f03d68113d50af93d3",
+			wantChannel:  "stable",
+		},
+		{
+			name: "Metadata without channel",
+			metadataReader: strings.NewReader(`version:
+  revision: 78666c8dc57e9f7548ca9f8dd0740fbf3c658db9
+project_type: package
+`),
+			wantRevision: "78666c8dc57e9f7548ca9f8dd0740fbf3c658db9",
+		},
+		{
+			name:           "Metadata without version",
+			metadataReader: strings.NewReader(`project_type: app`),
+		},
+		{
+			name:           "Empty metadata",
+			metadataReader: strings.NewReader(""),
+		},
+		{
+			name:           "Invalid metadata",
+			metadataReader: strings.NewReader("version: ["),
+			wantErr:        "yaml: line 1: did not find expected node content",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRevision, gotChannel, err := parseMetadataRevision(tt.metadataReader)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantRevision, gotRevision)
+				require.Equal(t, tt.wantChannel, gotChannel)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	"io"
+	"path/filepath"
+)
+
+const miseToolsTable = "tools"
+
+// MiseConfigRelPaths lists the project level mise (formerly rtx) config files in mise's precedence order, the local configs override the shared ones.
+var MiseConfigRelPaths = []string{
+	".mise.local.toml",
+	"mise.local.toml",
+	".mise.toml",
+	".mise/config.toml",
+	"mise.toml",
+	".config/mise.toml",
+	".config/mise/config.toml",
+	".rtx.toml",
+}
+
+type MiseVersionReader struct {
+	fileOpener FileOpener
+}
+
+func NewMiseVersionReader(fileOpener FileOpener) MiseVersionReader {
+	return MiseVersionReader{
+		fileOpener: fileOpener,
+	}
+}
+
+// ReadSDKVersion returns the Flutter version and channel pinned by mise and the project relative path of the config file it was read from.
+func (r MiseVersionReader) ReadSDKVersion(projectRootDir string) (*VersionConstraint, string, string, error) {
+	for _, configRelPath := range MiseConfigRelPaths {
+		miseConfigPth := filepath.Join(projectRootDir, configRelPath)
+		f, err := r.fileOpener.OpenReaderIfExists(miseConfigPth)
+		if err != nil {
+			return nil, "", "", err
+		}
+
+		if f == nil {
+			continue
+		}
+
+		version, channel, err := parseMiseFlutterVersion(f)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if version == nil && channel == "" {
+			continue
+		}
+
+		return version, channel, configRelPath, nil
+	}
+
+	return nil, "", "", nil
+}
+
+// parseMiseFlutterVersion reads the flutter tool's version, mise treats partial versions (3.22) as the newest matching release.
+func parseMiseFlutterVersion(miseConfigReader io.Reader) (*VersionConstraint, string, error) {
+	versionStr, err := readTOMLToolVersion(miseConfigReader, miseToolsTable, "flutter")
+	if err != nil {
+		return nil, "", err
+	}
+
+	version, channel := parseToolFlutterVersion(versionStr)
+	return version, channel, nil
+}
@@ -0,0 +1,151 @@
+package sdk
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/bitrise-io/go-flutter/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseMiseFlutterVersion(t *testing.T) {
+	tests := []struct {
+		name             string
+		miseConfigReader io.Reader
+		wantFlutterSDK   string
+		wantChannel      string
+		wantAllows       []string
+		wantDisallows    []string
+	}{
+		{
+			name: "Version with channel",
+			miseConfigReader: strings.NewReader(`[tools]
+flutter = "3.22.2-stable"`),
+			wantFlutterSDK: "3.22.2",
+			wantChannel:    "stable",
+		},
+		{
+			name: "Version without channel",
+			miseConfigReader: strings.NewReader(`[tools]
+flutter = "3.22.2"`),
+			wantFlutterSDK: "3.22.2",
+		},
+		{
+			name: "Channel head",
+			miseConfigReader: strings.NewReader(`[tools]
+flutter = "beta"`),
+			wantChannel: "beta",
+		},
+		{
+			name: "Master channel",
+			miseConfigReader: strings.NewReader(`[tools]
+flutter = "master"`),
+			wantChannel: "master",
+		},
+		{
+			name: "Latest",
+			miseConfigReader: strings.NewReader(`[tools]
+flutter = "latest"`),
+		},
+		{
+			name: "Fuzzy version",
+			miseConfigReader: strings.NewReader(`[tools]
+flutter = "3.22"`),
+			wantFlutterSDK: "3.22",
+			wantAllows:     []string{"3.22.5"},
+			wantDisallows:  []string{"3.23.0"},
+		},
+		{
+			name: "Unknown version",
+			miseConfigReader: strings.NewReader(`[tools]
+flutter = "ref:main"`),
+		},
+		{
+			name: "No Flutter version",
+			miseConfigReader: strings.NewReader(`[tools]
+node = "20"`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFlutterSDK, gotChannel, err := parseMiseFlutterVersion(tt.miseConfigReader)
+			require.NoError(t, err)
+			if tt.wantFlutterSDK == "" {
+				require.Nil(t, gotFlutterSDK)
+			} else {
+				require.Equal(t, tt.wantFlutterSDK, gotFlutterSDK.String())
+			}
+			for _, version := range tt.wantAllows {
+				require.True(t, gotFlutterSDK.Constraint.Check(semver.MustParse(version)), version)
+			}
+			for _, version := range tt.wantDisallows {
+				require.False(t, gotFlutterSDK.Constraint.Check(semver.MustParse(version)), version)
+			}
+			require.Equal(t, tt.wantChannel, gotChannel)
+		})
+	}
+}
+
+func TestMiseVersionReader_ReadSDKVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		files          map[string]string
+		wantFlutterSDK string
+		wantChannel    string
+		wantConfigPath string
+	}{
+		{
+			name: "Local config takes precedence",
+			files: map[string]string{
+				"mise.toml":       "[tools]\nflutter = \"3.19.6\"",
+				"mise.local.toml": "[tools]\nflutter = \"3.22.2-stable\"",
+			},
+			wantFlutterSDK: "3.22.2",
+			wantChannel:    "stable",
+			wantConfigPath: "mise.local.toml",
+		},
+		{
+			name: "Config without flutter is skipped",
+			files: map[string]string{
+				".mise/config.toml":        "[tools]\nnode = \"20\"",
+				".config/mise/config.toml": "[tools]\nflutter = \"3.19.6\"",
+			},
+			wantFlutterSDK: "3.19.6",
+			wantConfigPath: ".config/mise/config.toml",
+		},
+		{
+			name: "Legacy rtx config",
+			files: map[string]string{
+				".rtx.toml": "[tools]\nflutter = \"beta\"",
+			},
+			wantChannel:    "beta",
+			wantConfigPath: ".rtx.toml",
+		},
+		{
+			name:  "No mise config",
+			files: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileOpener := new(mocks.FileManager)
+			for pth, content := range tt.files {
+				fileOpener.On("OpenReaderIfExists", pth).Return(strings.NewReader(content), nil)
+			}
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			gotVersion, gotChannel, gotConfigPath, err := NewMiseVersionReader(fileOpener).ReadSDKVersion("")
+			require.NoError(t, err)
+			if tt.wantFlutterSDK == "" {
+				require.Nil(t, gotVersion)
+			} else {
+				require.Equal(t, tt.wantFlutterSDK, gotVersion.String())
+			}
+			require.Equal(t, tt.wantChannel, gotChannel)
+			require.Equal(t, tt.wantConfigPath, gotConfigPath)
+		})
+	}
+}
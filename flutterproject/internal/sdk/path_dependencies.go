@@ -0,0 +1,175 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PathDependency is a package the project depends on through a path dependency, directly or transitively.
+type PathDependency struct {
+	Name string
+	// PubspecPath is the project relative path of the package's pubspec.yaml.
+	PubspecPath    string
+	FlutterVersion *VersionConstraint
+	DartVersion    *VersionConstraint
+}
+
+type PathDependencyReader struct {
+	fileOpener FileOpener
+}
+
+func NewPathDependencyReader(fileOpener FileOpener) PathDependencyReader {
+	return PathDependencyReader{
+		fileOpener: fileOpener,
+	}
+}
+
+/*
+ReadPathDependencies returns the SDK requirements of the project's path dependencies, in the order they are found.
+
+Like pub, only the project's own dev_dependencies are followed, the path dependencies of the dependencies are followed
+transitively and the project's dependency_overrides replace the dependencies of every package.
+Packages without a pubspec.yaml are skipped, so are the packages with an invalid pubspec.yaml or SDK constraint:
+pub reports these when resolving the dependencies, they shouldn't prevent picking an SDK.
+*/
+func (r PathDependencyReader) ReadPathDependencies(projectRootDir string) ([]PathDependency, error) {
+	var dependencies []PathDependency
+	var overrides map[string]yaml.Node
+	visited := map[string]bool{".": true}
+	packageDirs := []string{"."}
+
+	for len(packageDirs) > 0 {
+		packageDir := packageDirs[0]
+		packageDirs = packageDirs[1:]
+
+		pubspecPth := filepath.Join(packageDir, PubspecRelPath)
+		if !filepath.IsAbs(pubspecPth) {
+			pubspecPth = filepath.Join(projectRootDir, pubspecPth)
+		}
+		f, err := r.fileOpener.OpenReaderIfExists(pubspecPth)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			continue
+		}
+
+		pubspec, err := parsePubspecPathDependencies(f)
+		if err != nil {
+			if packageDir == "." {
+				return nil, fmt.Errorf("failed to parse %s: %w", pubspecPth, err)
+			}
+			continue
+		}
+
+		if packageDir == "." {
+			overrides = pubspec.DependencyOverrides
+		} else if dependency, ok := pubspec.pathDependency(filepath.Join(packageDir, PubspecRelPath)); ok {
+			dependencies = append(dependencies, dependency)
+		}
+
+		for _, dir := range pubspec.dependencyDirs(packageDir, overrides) {
+			if visited[dir] {
+				continue
+			}
+			visited[dir] = true
+			packageDirs = append(packageDirs, dir)
+		}
+	}
+
+	return dependencies, nil
+}
+
+type pathDependencyPubspec struct {
+	Name        string `yaml:"name"`
+	Environment struct {
+		Dart    string `yaml:"sdk"`
+		Flutter string `yaml:"flutter"`
+	} `yaml:"environment"`
+	Dependencies        map[string]yaml.Node `yaml:"dependencies"`
+	DevDependencies     map[string]yaml.Node `yaml:"dev_dependencies"`
+	DependencyOverrides map[string]yaml.Node `yaml:"dependency_overrides"`
+}
+
+func parsePubspecPathDependencies(pubspecReader io.Reader) (pathDependencyPubspec, error) {
+	var pubspec pathDependencyPubspec
+	// an empty pubspec.yaml has no dependencies
+	if err := yaml.NewDecoder(pubspecReader).Decode(&pubspec); err != nil && !errors.Is(err, io.EOF) {
+		return pathDependencyPubspec{}, err
+	}
+	return pubspec, nil
+}
+
+// pathDependency returns the SDK requirements of the package, false if a constraint is invalid.
+func (p pathDependencyPubspec) pathDependency(pubspecPth string) (PathDependency, bool) {
+	dependency := PathDependency{Name: p.Name, PubspecPath: pubspecPth}
+	var err error
+	if p.Environment.Flutter != "" {
+		if dependency.FlutterVersion, err = NewVersionConstraint(p.Environment.Flutter); err != nil {
+			return PathDependency{}, false
+		}
+	}
+	if p.Environment.Dart != "" {
+		if dependency.DartVersion, err = NewVersionConstraint(p.Environment.Dart); err != nil {
+			return PathDependency{}, false
+		}
+	}
+	return dependency, true
+}
+
+/*
+dependencyDirs returns the project relative directories of the package's path dependencies sorted by name.
+The dev dependencies are only used by the root package, the root's overrides replace the dependencies of every package.
+Override paths are relative to the root package, dependency paths to the package.
+*/
+func (p pathDependencyPubspec) dependencyDirs(packageDir string, overrides map[string]yaml.Node) []string {
+	type dependencySource struct {
+		node yaml.Node
+		dir  string
+	}
+
+	dependencySets := []map[string]yaml.Node{p.Dependencies}
+	if packageDir == "." {
+		dependencySets = append(dependencySets, p.DevDependencies)
+	}
+
+	sources := map[string]dependencySource{}
+	for _, dependencies := range dependencySets {
+		for name, source := range dependencies {
+			sources[name] = dependencySource{node: source, dir: packageDir}
+		}
+	}
+	for name, source := range overrides {
+		if _, ok := sources[name]; ok {
+			sources[name] = dependencySource{node: source, dir: "."}
+		}
+	}
+
+	var names []string
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var dirs []string
+	for _, name := range names {
+		source := sources[name]
+		var dependency struct {
+			Path string `yaml:"path"`
+		}
+		if source.node.Kind != yaml.MappingNode || source.node.Decode(&dependency) != nil || dependency.Path == "" {
+			continue
+		}
+		dir := filepath.FromSlash(dependency.Path)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(source.dir, dir)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
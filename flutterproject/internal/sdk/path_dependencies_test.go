@@ -0,0 +1,164 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-flutter/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathDependencyReader_ReadPathDependencies(t *testing.T) {
+	type dependency struct {
+		name           string
+		pubspecPath    string
+		flutterVersion string
+		dartVersion    string
+	}
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []dependency
+		wantErr string
+	}{
+		{
+			name: "Direct, transitive and dev path dependencies",
+			files: map[string]string{
+				"pubspec.yaml": `name: app
+dependencies:
+  flutter:
+    sdk: flutter
+  core:
+    path: packages/core
+  http: ^1.0.0
+dev_dependencies:
+  lints:
+    path: ../lints
+`,
+				"packages/core/pubspec.yaml": `name: core
+environment:
+  sdk: ">=3.0.0 <4.0.0"
+  flutter: ">=3.10.0"
+dependencies:
+  utils:
+    path: ../utils
+dev_dependencies:
+  test_utils:
+    path: ../test_utils
+`,
+				"packages/utils/pubspec.yaml": "name: utils\nenvironment:\n  sdk: ^3.2.0\n",
+				"../lints/pubspec.yaml":       "name: lints\n",
+			},
+			want: []dependency{
+				{name: "core", pubspecPath: "packages/core/pubspec.yaml", flutterVersion: ">=3.10.0", dartVersion: ">=3.0.0 <4.0.0"},
+				{name: "lints", pubspecPath: "../lints/pubspec.yaml"},
+				{name: "utils", pubspecPath: "packages/utils/pubspec.yaml", dartVersion: "^3.2.0"},
+			},
+		},
+		{
+			name: "Dependency override",
+			files: map[string]string{
+				"pubspec.yaml": `name: app
+dependencies:
+  core:
+    path: packages/core
+dependency_overrides:
+  core:
+    path: forks/core
+`,
+				"packages/core/pubspec.yaml": "name: core\nenvironment:\n  sdk: ^3.0.0\n",
+				"forks/core/pubspec.yaml":    "name: core\nenvironment:\n  sdk: ^3.1.0\n",
+			},
+			want: []dependency{
+				{name: "core", pubspecPath: "forks/core/pubspec.yaml", dartVersion: "^3.1.0"},
+			},
+		},
+		{
+			name: "Dependency cycle and missing package",
+			files: map[string]string{
+				"pubspec.yaml":   "name: app\ndependencies:\n  a:\n    path: a\n  missing:\n    path: missing\n",
+				"a/pubspec.yaml": "name: a\ndependencies:\n  b:\n    path: ../b\n",
+				"b/pubspec.yaml": "name: b\ndependencies:\n  a:\n    path: ../a\n",
+			},
+			want: []dependency{
+				{name: "a", pubspecPath: "a/pubspec.yaml"},
+				{name: "b", pubspecPath: "b/pubspec.yaml"},
+			},
+		},
+		{
+			name: "Transitive dependency override",
+			files: map[string]string{
+				"pubspec.yaml": `name: app
+dependencies:
+  core:
+    path: packages/core
+dependency_overrides:
+  utils:
+    path: forks/utils
+  unused:
+    path: unused
+`,
+				"packages/core/pubspec.yaml":  "name: core\ndependencies:\n  utils:\n    path: ../utils\n",
+				"packages/utils/pubspec.yaml": "name: utils\nenvironment:\n  sdk: ^3.0.0\n",
+				"forks/utils/pubspec.yaml":    "name: utils\nenvironment:\n  sdk: ^3.1.0\n",
+				"unused/pubspec.yaml":         "name: unused\n",
+			},
+			want: []dependency{
+				{name: "core", pubspecPath: "packages/core/pubspec.yaml"},
+				{name: "utils", pubspecPath: "forks/utils/pubspec.yaml", dartVersion: "^3.1.0"},
+			},
+		},
+		{
+			name: "Invalid constraint and pubspec.yaml are skipped",
+			files: map[string]string{
+				"pubspec.yaml":   "name: app\ndependencies:\n  a:\n    path: a\n  b:\n    path: b\n",
+				"a/pubspec.yaml": "name: a\nenvironment:\n  sdk: \">=3.0.0 <\"\ndependencies:\n  c:\n    path: ../c\n",
+				"b/pubspec.yaml": "- name: b\n",
+				"c/pubspec.yaml": "name: c\nenvironment:\n  flutter: \">=3.10.0\"\n",
+			},
+			want: []dependency{
+				{name: "c", pubspecPath: "c/pubspec.yaml", flutterVersion: ">=3.10.0"},
+			},
+		},
+		{
+			name:    "Invalid project pubspec.yaml",
+			files:   map[string]string{"pubspec.yaml": "- name: app\n"},
+			wantErr: "failed to parse pubspec.yaml",
+		},
+		{
+			name:  "No pubspec.yaml",
+			files: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileOpener := new(mocks.FileManager)
+			for pth, content := range tt.files {
+				fileOpener.On("OpenReaderIfExists", pth).Return(strings.NewReader(content), nil)
+			}
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			got, err := NewPathDependencyReader(fileOpener).ReadPathDependencies("")
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var gotDependencies []dependency
+			for _, d := range got {
+				gotDependency := dependency{name: d.Name, pubspecPath: d.PubspecPath}
+				if d.FlutterVersion != nil {
+					gotDependency.flutterVersion = d.FlutterVersion.String()
+				}
+				if d.DartVersion != nil {
+					gotDependency.dartVersion = d.DartVersion.String()
+				}
+				gotDependencies = append(gotDependencies, gotDependency)
+			}
+			require.Equal(t, tt.want, gotDependencies)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package sdk
+
+import (
+	"regexp"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var commitHashExp = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+/*
+FlutterPin is the Flutter SDK pinned by a version manager, which is one of:
+- a release version, optionally with its channel (3.22.2, 3.22.2@beta)
+- the head of a channel (stable, beta, master)
+- a framework commit (a full, 40 character commit hash)
+*/
+type FlutterPin struct {
+	Version *semver.Version
+	Channel string
+	Commit  string
+}
+
+func (p FlutterPin) IsChannelHead() bool {
+	return p.Version == nil && p.Commit == "" && p.Channel != ""
+}
+
+func (p FlutterPin) IsCommit() bool {
+	return p.Commit != ""
+}
+
+func parseFlutterPin(versionStr, channel string) (*FlutterPin, error) {
+	switch {
+	case versionStr == "":
+		return nil, nil
+	case isChannelName(versionStr):
+		return &FlutterPin{Channel: versionStr}, nil
+	case commitHashExp.MatchString(versionStr):
+		return &FlutterPin{Commit: versionStr, Channel: channel}, nil
+	}
+
+	version, err := semver.NewVersion(versionStr)
+	if err != nil {
+		return nil, err
+	}
+	return &FlutterPin{Version: version, Channel: channel}, nil
+}
+
+func isChannelName(value string) bool {
+	switch value {
+	case stableChannel, betaChannel, "master", "main", "dev":
+		return true
+	default:
+		return false
+	}
+}
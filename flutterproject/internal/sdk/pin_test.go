@@ -0,0 +1,82 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseFlutterPin(t *testing.T) {
+	tests := []struct {
+		name            string
+		versionStr      string
+		channel         string
+		wantVersion     string
+		wantChannel     string
+		wantCommit      string
+		wantChannelHead bool
+		wantErr         string
+	}{
+		{
+			name:        "Version",
+			versionStr:  "3.22.2",
+			wantVersion: "3.22.2",
+		},
+		{
+			name:        "Version with channel",
+			versionStr:  "3.22.2",
+			channel:     "beta",
+			wantVersion: "3.22.2",
+			wantChannel: "beta",
+		},
+		{
+			name:            "Channel head",
+			versionStr:      "stable",
+			wantChannel:     "stable",
+			wantChannelHead: true,
+		},
+		{
+			name:            "Master channel head",
+			versionStr:      "master",
+			wantChannel:     "master",
+			wantChannelHead: true,
+		},
+		{
+			name:       "Commit",
+			versionStr: "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+			wantCommit: "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+		},
+		{
+			name: "No pin",
+		},
+		{
+			name:       "Invalid version",
+			versionStr: "my-fork",
+			wantErr:    "Invalid Semantic Version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFlutterPin(tt.versionStr, tt.channel)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.versionStr == "" {
+				require.Nil(t, got)
+				return
+			}
+
+			if tt.wantVersion == "" {
+				require.Nil(t, got.Version)
+			} else {
+				require.Equal(t, tt.wantVersion, got.Version.String())
+			}
+			require.Equal(t, tt.wantChannel, got.Channel)
+			require.Equal(t, tt.wantCommit, got.Commit)
+			require.Equal(t, tt.wantChannelHead, got.IsChannelHead())
+			require.Equal(t, tt.wantCommit != "", got.IsCommit())
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"io"
+	"path/filepath"
+)
+
+const ProtoConfigRelPath = ".prototools"
+
+type ProtoVersionReader struct {
+	fileOpener FileOpener
+}
+
+func NewProtoVersionReader(fileOpener FileOpener) ProtoVersionReader {
+	return ProtoVersionReader{
+		fileOpener: fileOpener,
+	}
+}
+
+func (r ProtoVersionReader) ReadSDKVersion(projectRootDir string) (*VersionConstraint, string, error) {
+	protoConfigPth := filepath.Join(projectRootDir, ProtoConfigRelPath)
+	f, err := r.fileOpener.OpenReaderIfExists(protoConfigPth)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if f == nil {
+		return nil, "", nil
+	}
+
+	return parseProtoFlutterVersion(f)
+}
+
+// parseProtoFlutterVersion reads the flutter tool's version, which is a top-level key in .prototools.
+func parseProtoFlutterVersion(protoConfigReader io.Reader) (*VersionConstraint, string, error) {
+	versionStr, err := readTOMLToolVersion(protoConfigReader, "", "flutter")
+	if err != nil {
+		return nil, "", err
+	}
+
+	version, channel := parseToolFlutterVersion(versionStr)
+	return version, channel, nil
+}
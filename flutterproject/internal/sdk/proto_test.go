@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseProtoFlutterVersion(t *testing.T) {
+	tests := []struct {
+		name              string
+		protoConfigReader io.Reader
+		wantFlutterSDK    string
+		wantChannel       string
+		wantAllows        []string
+		wantDisallows     []string
+	}{
+		{
+			name: "Real .prototools",
+			protoConfigReader: strings.NewReader(`node = "20.11.0"
+flutter = "3.22.2"
+
+[plugins]
+flutter = "source:https://raw.githubusercontent.com/example/proto-flutter-plugin/main/plugin.toml"`),
+			wantFlutterSDK: "3.22.2",
+		},
+		{
+			name:              "Version with channel",
+			protoConfigReader: strings.NewReader(`flutter = "3.22.2-beta"`),
+			wantFlutterSDK:    "3.22.2",
+			wantChannel:       "beta",
+		},
+		{
+			name:              "Channel",
+			protoConfigReader: strings.NewReader(`flutter = "stable"`),
+			wantChannel:       "stable",
+		},
+		{
+			name:              "Tilde range",
+			protoConfigReader: strings.NewReader(`flutter = "~3.22"`),
+			wantFlutterSDK:    "~3.22",
+			wantAllows:        []string{"3.22.5"},
+			wantDisallows:     []string{"3.23.0"},
+		},
+		{
+			name:              "Caret range",
+			protoConfigReader: strings.NewReader(`flutter = "^3.22"`),
+			wantFlutterSDK:    "^3.22.0",
+		},
+		{
+			name:              "Partial version",
+			protoConfigReader: strings.NewReader(`flutter = "3.22"`),
+			wantFlutterSDK:    "3.22",
+			wantAllows:        []string{"3.22.0", "3.22.5"},
+			wantDisallows:     []string{"3.21.9", "3.23.0"},
+		},
+		{
+			name:              "Latest",
+			protoConfigReader: strings.NewReader(`flutter = "latest"`),
+		},
+		{
+			name:              "Empty .prototools",
+			protoConfigReader: strings.NewReader(""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFlutterSDK, gotChannel, err := parseProtoFlutterVersion(tt.protoConfigReader)
+			require.NoError(t, err)
+			if tt.wantFlutterSDK == "" {
+				require.Nil(t, gotFlutterSDK)
+			} else {
+				require.Equal(t, tt.wantFlutterSDK, gotFlutterSDK.String())
+			}
+			for _, version := range tt.wantAllows {
+				require.True(t, gotFlutterSDK.Constraint.Check(semver.MustParse(version)), version)
+			}
+			for _, version := range tt.wantDisallows {
+				require.False(t, gotFlutterSDK.Constraint.Check(semver.MustParse(version)), version)
+			}
+			require.Equal(t, tt.wantChannel, gotChannel)
+		})
+	}
+}
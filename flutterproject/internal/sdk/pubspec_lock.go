@@ -7,7 +7,7 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-const pubspecLockRelPath = "pubspec.lock"
+const PubspecLockRelPath = "pubspec.lock"
 
 type PubspecLockVersionReader struct {
 	fileOpener FileOpener
@@ -20,7 +20,7 @@ func NewPubspecLockVersionReader(fileOpener FileOpener) PubspecLockVersionReader
 }
 
 func (r PubspecLockVersionReader) ReadSDKVersions(projectRootDir string) (*VersionConstraint, *VersionConstraint, error) {
-	pubspecLockPth := filepath.Join(projectRootDir, pubspecLockRelPath)
+	pubspecLockPth := filepath.Join(projectRootDir, PubspecLockRelPath)
 	f, err := r.fileOpener.OpenReaderIfExists(pubspecLockPth)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,80 @@
+package sdk
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const PuroConfigRelPath = ".puro.json"
+
+type PuroVersionReader struct {
+	fileOpener FileOpener
+}
+
+func NewPuroVersionReader(fileOpener FileOpener) PuroVersionReader {
+	return PuroVersionReader{
+		fileOpener: fileOpener,
+	}
+}
+
+func (r PuroVersionReader) ReadSDKVersion(projectRootDir string) (*semver.Version, string, error) {
+	puroConfigPth := filepath.Join(projectRootDir, PuroConfigRelPath)
+	f, err := r.fileOpener.OpenReaderIfExists(puroConfigPth)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if f == nil {
+		return nil, "", nil
+	}
+
+	versionStr, channel, err := parsePuroFlutterVersion(f)
+	if err != nil {
+		return nil, "", err
+	}
+	if versionStr == "" {
+		return nil, channel, nil
+	}
+
+	version, err := semver.NewVersion(versionStr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return version, channel, nil
+}
+
+/*
+parsePuroFlutterVersion reads the environment pinned in .puro.json.
+
+Puro pins environments, not versions, the environment name is interpreted as:
+- a channel, for puro's default environments (stable, beta, master)
+- a version, if the environment is named after a Flutter version (like 3.22.2 or 3.22.2-stable)
+- nothing, for custom environment names
+*/
+func parsePuroFlutterVersion(puroConfigReader io.Reader) (string, string, error) {
+	type puroConfig struct {
+		Env string `json:"env"`
+	}
+
+	var config puroConfig
+	d := json.NewDecoder(puroConfigReader)
+	if err := d.Decode(&config); err != nil {
+		return "", "", err
+	}
+
+	switch config.Env {
+	case stableChannel, betaChannel, "master":
+		return "", config.Env, nil
+	}
+
+	versionStr, channel := splitChannelSuffix(config.Env)
+	if _, err := semver.StrictNewVersion(versionStr); err != nil {
+		return "", "", nil
+	}
+
+	return versionStr, channel, nil
+}
@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parsePuroFlutterVersion(t *testing.T) {
+	tests := []struct {
+		name             string
+		puroConfigReader io.Reader
+		wantFlutterSDK   string
+		wantChannel      string
+		wantErr          string
+	}{
+		{
+			name:             "Environment named after a version",
+			puroConfigReader: strings.NewReader(`{"env": "3.22.2"}`),
+			wantFlutterSDK:   "3.22.2",
+		},
+		{
+			name:             "Environment named after a version with channel",
+			puroConfigReader: strings.NewReader(`{"env": "3.22.2-stable"}`),
+			wantFlutterSDK:   "3.22.2",
+			wantChannel:      "stable",
+		},
+		{
+			name:             "Default channel environment",
+			puroConfigReader: strings.NewReader(`{"env": "beta"}`),
+			wantChannel:      "beta",
+		},
+		{
+			name:             "Custom environment",
+			puroConfigReader: strings.NewReader(`{"env": "my_env"}`),
+		},
+		{
+			name:             "Empty .puro.json",
+			puroConfigReader: strings.NewReader(""),
+			wantErr:          "EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFlutterSDK, gotChannel, err := parsePuroFlutterVersion(tt.puroConfigReader)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantFlutterSDK, gotFlutterSDK)
+				require.Equal(t, tt.wantChannel, gotChannel)
+			}
+		})
+	}
+}
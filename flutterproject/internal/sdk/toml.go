@@ -0,0 +1,103 @@
+package sdk
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+readTOMLToolVersion reads a tool's version from a TOML config file, like mise.toml or .prototools.
+Only the subset of TOML used by these tools' configs is supported, the tool's value can be:
+- a string: flutter = "3.22.2-stable"
+- an array, its first element is returned: flutter = ["3.22.2-stable", "3.19.6-stable"]
+- an inline table with a version key: flutter = { version = "3.22.2-stable" }
+
+table is the name of the table the tool is defined in, an empty table means the top-level keys.
+*/
+func readTOMLToolVersion(r io.Reader, table, tool string) (string, error) {
+	currentTable := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			currentTable = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+
+		if currentTable != table {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		if key != tool {
+			continue
+		}
+
+		return parseTOMLToolVersion(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+func parseTOMLToolVersion(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "["):
+		elements := strings.Split(strings.Trim(value, "[]"), ",")
+		return unquoteTOMLString(strings.TrimSpace(elements[0]))
+	case strings.HasPrefix(value, "{"):
+		for _, field := range strings.Split(strings.Trim(value, "{}"), ",") {
+			key, fieldValue, ok := strings.Cut(field, "=")
+			if ok && strings.TrimSpace(key) == "version" {
+				return unquoteTOMLString(strings.TrimSpace(fieldValue))
+			}
+		}
+		return "", nil
+	default:
+		return unquoteTOMLString(value)
+	}
+}
+
+func unquoteTOMLString(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) > 1 {
+		return value[1 : len(value)-1], nil
+	}
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid TOML string: %s", value)
+	}
+	return unquoted, nil
+}
+
+// stripTOMLComment removes the comment from a line, # characters in quoted strings are kept.
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
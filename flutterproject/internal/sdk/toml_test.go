@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readTOMLToolVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		table       string
+		wantVersion string
+		wantErr     string
+	}{
+		{
+			name: "String value",
+			config: `[env]
+FLUTTER_ROOT = "/opt/flutter"
+
+[tools]
+node = "20"
+flutter = "3.22.2-stable" # pinned for the release train`,
+			table:       "tools",
+			wantVersion: "3.22.2-stable",
+		},
+		{
+			name: "Array value",
+			config: `[tools]
+flutter = ["3.22.2-stable", "3.19.6-stable"]`,
+			table:       "tools",
+			wantVersion: "3.22.2-stable",
+		},
+		{
+			name: "Inline table value",
+			config: `[tools]
+flutter = { version = '3.22.2-stable', os = ["macos"] }`,
+			table:       "tools",
+			wantVersion: "3.22.2-stable",
+		},
+		{
+			name: "Top-level key",
+			config: `flutter = "3.22.2"
+
+[plugins]
+flutter = "source:https://example.com/flutter_plugin.toml"`,
+			table:       "",
+			wantVersion: "3.22.2",
+		},
+		{
+			name: "Key in an other table",
+			config: `[alias]
+flutter = "asdf:asdf-community/asdf-flutter"`,
+			table:       "tools",
+			wantVersion: "",
+		},
+		{
+			name: "Invalid string",
+			config: `[tools]
+flutter = "3.22.2`,
+			table:   "tools",
+			wantErr: `invalid TOML string: "3.22.2`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTOMLToolVersion(strings.NewReader(tt.config), tt.table, "flutter")
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantVersion, got)
+			}
+		})
+	}
+}
@@ -2,13 +2,14 @@ package sdk
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 )
 
 /*
-VersionConstraint stores either an exact version or a version constraint.
-Version is a valid semantic version, constraint supports the Caret and the traditional syntax.
+VersionConstraint stores either an exact version or a version constraint, following pub's version constraint syntax and semantics
+(see Constraint).
 
 Caret syntax
 - ^1.2.3 = >=1.2.3 <2.0.0
@@ -24,27 +25,43 @@ Traditional syntax
 */
 type VersionConstraint struct {
 	Version    *semver.Version
-	Constraint *semver.Constraints
+	Constraint *Constraint
 }
 
 func NewVersionConstraint(version string) (*VersionConstraint, error) {
-	var v *semver.Version
-	var c *semver.Constraints
-
-	var vErr error
-	v, vErr = semver.NewVersion(version)
-	if vErr != nil {
-		var cErr error
-		c, cErr = semver.NewConstraint(version)
-		if cErr != nil {
-			return nil, fmt.Errorf("invalid version (%s): not a semantic version (%s) nor a version constraint (%s)", version, vErr, cErr)
-		}
+	v, vErr := parseVersion(version)
+	if vErr == nil {
+		return &VersionConstraint{Version: v}, nil
 	}
 
-	return &VersionConstraint{
-		Version:    v,
-		Constraint: c,
-	}, nil
+	c, cErr := ParseConstraint(version)
+	if cErr != nil {
+		return nil, fmt.Errorf("invalid version (%s): not a semantic version (%s) nor a version constraint (%s)", version, vErr, cErr)
+	}
+
+	return &VersionConstraint{Constraint: c}, nil
+}
+
+// parseVersion parses a complete pub version (major.minor.patch, with optional pre-release and build parts).
+func parseVersion(version string) (*semver.Version, error) {
+	if !completeVersionExp.MatchString(strings.TrimSpace(version)) {
+		return nil, semver.ErrInvalidSemVer
+	}
+	return semver.NewVersion(strings.TrimSpace(version))
+}
+
+/*
+MinimumOnly returns the requirement with its lower bound only, this is how Flutter (and pub) enforce
+the environment.flutter constraint of pubspec.yaml: 3.7.0, ^3.7.0 and >=3.7.0 <3.8.0 all mean >=3.7.0.
+*/
+func (c VersionConstraint) MinimumOnly() *VersionConstraint {
+	if c.Version != nil {
+		return &VersionConstraint{Constraint: NewVersionRange(c.Version, true, nil, false)}
+	}
+	if c.Constraint != nil {
+		return &VersionConstraint{Constraint: c.Constraint.LowerBound()}
+	}
+	return nil
 }
 
 func (c VersionConstraint) String() string {
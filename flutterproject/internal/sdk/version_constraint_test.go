@@ -33,15 +33,30 @@ func TestNewVersionConstraint(t *testing.T) {
 			wantConstraint: ">=1.2.3 <2.0.0",
 			wantSource:     "test_source",
 		},
+		{
+			name:           "Version constraint - any",
+			version:        "any",
+			wantConstraint: "any",
+		},
+		{
+			name:           "Version constraint - Pre-release minimum",
+			version:        ">=3.0.0-0 <4.0.0",
+			wantConstraint: ">=3.0.0-0 <4.0.0",
+		},
+		{
+			name:    "Partial version",
+			version: "3.22",
+			wantErr: `invalid version (3.22): not a semantic version (Invalid Semantic Version) nor a version constraint (could not parse version "3.22", unknown text at "3.22")`,
+		},
 		{
 			name:    "Empty version",
 			version: "",
-			wantErr: "invalid version (): not a semantic version (Invalid Semantic Version) nor a version constraint (improper constraint: )",
+			wantErr: "invalid version (): not a semantic version (Invalid Semantic Version) nor a version constraint (cannot parse an empty string)",
 		},
 		{
 			name:    "Invalid version",
 			version: "asdf",
-			wantErr: "invalid version (asdf): not a semantic version (Invalid Semantic Version) nor a version constraint (improper constraint: asdf)",
+			wantErr: "invalid version (asdf): not a semantic version (Invalid Semantic Version) nor a version constraint (could not parse version \"asdf\", unknown text at \"asdf\")",
 		},
 	}
 	for _, tt := range tests {
@@ -70,3 +85,25 @@ func TestNewVersionConstraint(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionConstraint_MinimumOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{name: "Exact version", version: "3.7.12", want: ">=3.7.12"},
+		{name: "Caret constraint", version: "^3.7.12", want: ">=3.7.12"},
+		{name: "Range constraint", version: ">=3.7.0 <3.8.0", want: ">=3.7.0"},
+		{name: "Upper bound only", version: "<3.16.0", want: "any"},
+		{name: "Exclusive minimum", version: ">3.7.0 <3.8.0", want: ">3.7.0"},
+		{name: "Empty constraint", version: ">=2.0.0 <1.0.0", want: "<empty>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			constraint, err := NewVersionConstraint(tt.version)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, constraint.MinimumOnly().String())
+		})
+	}
+}
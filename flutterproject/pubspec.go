@@ -0,0 +1,468 @@
+package flutterproject
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is the location of a node in a YAML file, errors about the node should point at it.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+func nodePosition(file string, node *yaml.Node) Position {
+	return Position{File: file, Line: node.Line, Column: node.Column}
+}
+
+/*
+Pubspec is the typed model of the project's pubspec.yaml.
+
+Keys not covered by the model are kept in Extra as YAML nodes, so are the unknown keys of the nested maps
+(environment, dependencies given as a map, git descriptions and the flutter section with its assets, fonts and plugin).
+Position returns the location of any node in the file, so errors can point at file:line.
+*/
+type Pubspec struct {
+	Name                string
+	Version             string
+	Description         string
+	PublishTo           string
+	Environment         PubspecEnvironment
+	Dependencies        []PubspecDependency
+	DevDependencies     []PubspecDependency
+	DependencyOverrides []PubspecDependency
+	Flutter             *PubspecFlutter
+	Extra               map[string]*yaml.Node
+
+	file string
+	root *yaml.Node
+}
+
+type PubspecEnvironment struct {
+	SDK     string
+	Flutter string
+	Extra   map[string]*yaml.Node
+}
+
+// PubspecDependencySource tells where a dependency is downloaded from.
+type PubspecDependencySource string
+
+const (
+	HostedDependency PubspecDependencySource = "hosted"
+	GitDependency    PubspecDependencySource = "git"
+	PathDependency   PubspecDependencySource = "path"
+	SDKDependency    PubspecDependencySource = "sdk"
+)
+
+/*
+PubspecDependency is an entry of the dependencies, dev_dependencies or dependency_overrides sections.
+
+Version is the version constraint as written, empty if the dependency allows any version.
+The source specific fields are only set for the dependency's Source: HostedName and HostedURL for hosted dependencies
+with a custom package repository, Git for git, Path for path and SDK for sdk dependencies.
+*/
+type PubspecDependency struct {
+	Name       string
+	Source     PubspecDependencySource
+	Version    string
+	HostedName string
+	HostedURL  string
+	Git        *PubspecGitDependency
+	Path       string
+	SDK        string
+	Position   Position
+	Extra      map[string]*yaml.Node
+}
+
+type PubspecGitDependency struct {
+	URL        string                `yaml:"url"`
+	Ref        string                `yaml:"ref"`
+	Path       string                `yaml:"path"`
+	TagPattern string                `yaml:"tag_pattern"`
+	Extra      map[string]*yaml.Node `yaml:"-"`
+}
+
+func (d *PubspecGitDependency) UnmarshalYAML(node *yaml.Node) error {
+	type gitDependency PubspecGitDependency
+	if err := node.Decode((*gitDependency)(d)); err != nil {
+		return err
+	}
+	d.Extra = extraKeys(node, "url", "ref", "path", "tag_pattern")
+	return nil
+}
+
+// PubspecFlutter is the flutter section of pubspec.yaml.
+type PubspecFlutter struct {
+	UsesMaterialDesign bool                  `yaml:"uses-material-design"`
+	Generate           bool                  `yaml:"generate"`
+	Assets             []PubspecAsset        `yaml:"assets"`
+	Fonts              []PubspecFontFamily   `yaml:"fonts"`
+	Plugin             *PubspecPlugin        `yaml:"plugin"`
+	Extra              map[string]*yaml.Node `yaml:"-"`
+}
+
+// PubspecAsset is an asset file or directory, assets with flavors are only bundled in the given flavors.
+type PubspecAsset struct {
+	Path     string
+	Flavors  []string
+	Position Position
+	Extra    map[string]*yaml.Node
+}
+
+func (a *PubspecAsset) UnmarshalYAML(node *yaml.Node) error {
+	a.Position = Position{Line: node.Line, Column: node.Column}
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Path)
+	}
+
+	var asset struct {
+		Path    string   `yaml:"path"`
+		Flavors []string `yaml:"flavors"`
+	}
+	if err := node.Decode(&asset); err != nil {
+		return err
+	}
+	a.Path = asset.Path
+	a.Flavors = asset.Flavors
+	a.Extra = extraKeys(node, "path", "flavors")
+	return nil
+}
+
+type PubspecFontFamily struct {
+	Family string                `yaml:"family"`
+	Fonts  []PubspecFont         `yaml:"fonts"`
+	Extra  map[string]*yaml.Node `yaml:"-"`
+}
+
+func (f *PubspecFontFamily) UnmarshalYAML(node *yaml.Node) error {
+	type fontFamily PubspecFontFamily
+	if err := node.Decode((*fontFamily)(f)); err != nil {
+		return err
+	}
+	f.Extra = extraKeys(node, "family", "fonts")
+	return nil
+}
+
+type PubspecFont struct {
+	Asset  string                `yaml:"asset"`
+	Weight int                   `yaml:"weight"`
+	Style  string                `yaml:"style"`
+	Extra  map[string]*yaml.Node `yaml:"-"`
+}
+
+func (f *PubspecFont) UnmarshalYAML(node *yaml.Node) error {
+	type font PubspecFont
+	if err := node.Decode((*font)(f)); err != nil {
+		return err
+	}
+	f.Extra = extraKeys(node, "asset", "weight", "style")
+	return nil
+}
+
+// PubspecPlugin is the plugin section of a Flutter plugin package, Platforms is keyed by the platform name (android, ios, web, ...).
+type PubspecPlugin struct {
+	Platforms map[string]PubspecPluginPlatform `yaml:"platforms"`
+	Extra     map[string]*yaml.Node            `yaml:"-"`
+}
+
+func (p *PubspecPlugin) UnmarshalYAML(node *yaml.Node) error {
+	type plugin PubspecPlugin
+	if err := node.Decode((*plugin)(p)); err != nil {
+		return err
+	}
+	p.Extra = extraKeys(node, "platforms")
+	return nil
+}
+
+type PubspecPluginPlatform struct {
+	Package            string                `yaml:"package"`
+	PluginClass        string                `yaml:"pluginClass"`
+	DartPluginClass    string                `yaml:"dartPluginClass"`
+	FFIPlugin          bool                  `yaml:"ffiPlugin"`
+	FileName           string                `yaml:"fileName"`
+	DefaultPackage     string                `yaml:"default_package"`
+	SharedDarwinSource bool                  `yaml:"sharedDarwinSource"`
+	Extra              map[string]*yaml.Node `yaml:"-"`
+}
+
+func (p *PubspecPluginPlatform) UnmarshalYAML(node *yaml.Node) error {
+	type pluginPlatform PubspecPluginPlatform
+	if err := node.Decode((*pluginPlatform)(p)); err != nil {
+		return err
+	}
+	p.Extra = extraKeys(node, "package", "pluginClass", "dartPluginClass", "ffiPlugin", "fileName", "default_package", "sharedDarwinSource")
+	return nil
+}
+
+// ParsePubspec parses a pubspec.yaml, file is the path used in the positions of the model and in the errors.
+func ParsePubspec(r io.Reader, file string) (Pubspec, error) {
+	var document yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		return Pubspec{}, err
+	}
+
+	root := &document
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return Pubspec{}, fmt.Errorf("%s: pubspec.yaml is not a map", nodePosition(file, root))
+	}
+
+	pubspec := Pubspec{Extra: map[string]*yaml.Node{}, file: file, root: root}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		var err error
+		switch key.Value {
+		case "name":
+			err = value.Decode(&pubspec.Name)
+		case "version":
+			err = value.Decode(&pubspec.Version)
+		case "description":
+			err = value.Decode(&pubspec.Description)
+		case "publish_to":
+			err = value.Decode(&pubspec.PublishTo)
+		case "environment":
+			pubspec.Environment, err = parsePubspecEnvironment(value)
+		case "dependencies":
+			pubspec.Dependencies, err = parsePubspecDependencies(file, value)
+		case "dev_dependencies":
+			pubspec.DevDependencies, err = parsePubspecDependencies(file, value)
+		case "dependency_overrides":
+			pubspec.DependencyOverrides, err = parsePubspecDependencies(file, value)
+		case "flutter":
+			pubspec.Flutter, err = parsePubspecFlutter(file, value)
+		default:
+			pubspec.Extra[key.Value] = value
+		}
+		if err != nil {
+			var positionErr pubspecPositionError
+			if errors.As(err, &positionErr) {
+				return Pubspec{}, err
+			}
+			return Pubspec{}, pubspecPositionError{position: nodePosition(file, value), err: fmt.Errorf("invalid %s: %w", key.Value, err)}
+		}
+	}
+
+	return pubspec, nil
+}
+
+// pubspecPositionError is an error, which points at the invalid node of pubspec.yaml.
+type pubspecPositionError struct {
+	position Position
+	err      error
+}
+
+func (e pubspecPositionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.position, e.err)
+}
+
+func (e pubspecPositionError) Unwrap() error {
+	return e.err
+}
+
+/*
+Position returns the location of the node at the given key path, for example Position("flutter", "assets", "0")
+for the first asset. Elements of a list are addressed by their index. Returns false if there is no such node.
+Map keys point at the key, so errors about a section point at its first line.
+*/
+func (p Pubspec) Position(path ...string) (Position, bool) {
+	if p.root == nil {
+		return Position{}, false
+	}
+
+	node, position := p.root, nodePosition(p.file, p.root)
+	for _, key := range path {
+		switch node.Kind {
+		case yaml.MappingNode:
+			found := false
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					position = nodePosition(p.file, node.Content[i])
+					node = node.Content[i+1]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return Position{}, false
+			}
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				return Position{}, false
+			}
+			node = node.Content[idx]
+			position = nodePosition(p.file, node)
+		default:
+			return Position{}, false
+		}
+	}
+	return position, true
+}
+
+// Dependency returns the named dependency, overrides take precedence over dependencies and dev dependencies.
+func (p Pubspec) Dependency(name string) (PubspecDependency, bool) {
+	for _, dependencies := range [][]PubspecDependency{p.DependencyOverrides, p.Dependencies, p.DevDependencies} {
+		for _, dependency := range dependencies {
+			if dependency.Name == name {
+				return dependency, true
+			}
+		}
+	}
+	return PubspecDependency{}, false
+}
+
+func parsePubspecDependencies(file string, node *yaml.Node) ([]PubspecDependency, error) {
+	if node.Tag == "!!null" {
+		return nil, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("not a map")
+	}
+
+	var dependencies []PubspecDependency
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		dependency, err := parsePubspecDependency(key.Value, value)
+		if err != nil {
+			return nil, pubspecPositionError{position: nodePosition(file, value), err: fmt.Errorf("invalid dependency %s: %w", key.Value, err)}
+		}
+		dependency.Position = nodePosition(file, key)
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
+func parsePubspecDependency(name string, node *yaml.Node) (PubspecDependency, error) {
+	dependency := PubspecDependency{Name: name, Source: HostedDependency}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag != "!!null" {
+			dependency.Version = node.Value
+		}
+		return dependency, nil
+	case yaml.MappingNode:
+	default:
+		return PubspecDependency{}, fmt.Errorf("not a version constraint or a map")
+	}
+
+	var description struct {
+		Version string    `yaml:"version"`
+		Hosted  yaml.Node `yaml:"hosted"`
+		Git     yaml.Node `yaml:"git"`
+		Path    string    `yaml:"path"`
+		SDK     string    `yaml:"sdk"`
+	}
+	if err := node.Decode(&description); err != nil {
+		return PubspecDependency{}, err
+	}
+	dependency.Version = description.Version
+	dependency.Extra = extraKeys(node, "version", "hosted", "git", "path", "sdk")
+
+	var sources []PubspecDependencySource
+	if description.Hosted.Kind != 0 {
+		sources = append(sources, HostedDependency)
+		if description.Hosted.Kind == yaml.ScalarNode {
+			dependency.HostedURL = description.Hosted.Value
+		} else {
+			var hosted struct {
+				Name string `yaml:"name"`
+				URL  string `yaml:"url"`
+			}
+			if err := description.Hosted.Decode(&hosted); err != nil {
+				return PubspecDependency{}, err
+			}
+			dependency.HostedName = hosted.Name
+			dependency.HostedURL = hosted.URL
+		}
+	}
+	if description.Git.Kind != 0 {
+		sources = append(sources, GitDependency)
+		dependency.Git = &PubspecGitDependency{}
+		if description.Git.Kind == yaml.ScalarNode {
+			dependency.Git.URL = description.Git.Value
+		} else if err := description.Git.Decode(dependency.Git); err != nil {
+			return PubspecDependency{}, err
+		}
+	}
+	if description.Path != "" {
+		sources = append(sources, PathDependency)
+		dependency.Path = description.Path
+	}
+	if description.SDK != "" {
+		sources = append(sources, SDKDependency)
+		dependency.SDK = description.SDK
+	}
+
+	if len(sources) > 1 {
+		return PubspecDependency{}, fmt.Errorf("more than one source: %s and %s", sources[0], sources[1])
+	}
+	if len(sources) == 1 {
+		dependency.Source = sources[0]
+	}
+	return dependency, nil
+}
+
+func parsePubspecFlutter(file string, node *yaml.Node) (*PubspecFlutter, error) {
+	if node.Tag == "!!null" {
+		return &PubspecFlutter{}, nil
+	}
+
+	var flutter PubspecFlutter
+	if err := node.Decode(&flutter); err != nil {
+		return nil, err
+	}
+	for i := range flutter.Assets {
+		flutter.Assets[i].Position.File = file
+	}
+
+	flutter.Extra = extraKeys(node, "uses-material-design", "generate", "assets", "fonts", "plugin")
+	return &flutter, nil
+}
+
+func parsePubspecEnvironment(node *yaml.Node) (PubspecEnvironment, error) {
+	if node.Tag == "!!null" {
+		return PubspecEnvironment{}, nil
+	}
+
+	var environment struct {
+		SDK     string `yaml:"sdk"`
+		Flutter string `yaml:"flutter"`
+	}
+	if err := node.Decode(&environment); err != nil {
+		return PubspecEnvironment{}, err
+	}
+	return PubspecEnvironment{
+		SDK:     environment.SDK,
+		Flutter: environment.Flutter,
+		Extra:   extraKeys(node, "sdk", "flutter"),
+	}, nil
+}
+
+// extraKeys returns the values of the map node's keys, which are not in known, nil if there are none.
+func extraKeys(node *yaml.Node, known ...string) map[string]*yaml.Node {
+	var extra map[string]*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if containsString(known, key) {
+			continue
+		}
+		if extra == nil {
+			extra = map[string]*yaml.Node{}
+		}
+		extra[key] = node.Content[i+1]
+	}
+	return extra
+}
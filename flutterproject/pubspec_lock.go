@@ -0,0 +1,129 @@
+package flutterproject
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/bitrise-io/go-flutter/flutterproject/internal/sdk"
+	"gopkg.in/yaml.v3"
+)
+
+// PubspecLockDependencyKind tells why a package is in the project's dependency graph.
+type PubspecLockDependencyKind string
+
+const (
+	DirectMainDependency       PubspecLockDependencyKind = "direct main"
+	DirectDevDependency        PubspecLockDependencyKind = "direct dev"
+	DirectOverriddenDependency PubspecLockDependencyKind = "direct overridden"
+	TransitiveDependency       PubspecLockDependencyKind = "transitive"
+)
+
+// PubspecLock is the typed model of the project's pubspec.lock, Packages are sorted by name.
+type PubspecLock struct {
+	Packages []PubspecLockPackage
+	SDKs     PubspecLockSDKs
+}
+
+type PubspecLockSDKs struct {
+	Dart    string `yaml:"dart"`
+	Flutter string `yaml:"flutter"`
+}
+
+// PubspecLockPackage is a resolved package, the fields of Description depend on the package's Source.
+type PubspecLockPackage struct {
+	Name        string
+	Dependency  PubspecLockDependencyKind
+	Source      PubspecDependencySource
+	Version     string
+	Description PubspecLockDescription
+}
+
+/*
+PubspecLockDescription tells where a resolved package was downloaded from:
+- hosted packages have a Name, URL and the SHA256 checksum of the archive
+- git packages have a URL, the Ref as written in pubspec.yaml, the ResolvedRef commit and the Path of the package in the repository
+- path packages have a Path, which is Relative to the project or absolute
+- sdk packages have the SDK name
+*/
+type PubspecLockDescription struct {
+	Name        string `yaml:"name"`
+	URL         string `yaml:"url"`
+	SHA256      string `yaml:"sha256"`
+	Ref         string `yaml:"ref"`
+	ResolvedRef string `yaml:"resolved-ref"`
+	Path        string `yaml:"path"`
+	Relative    bool   `yaml:"relative"`
+	SDK         string `yaml:"-"`
+}
+
+func (d *PubspecLockDescription) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&d.SDK)
+	}
+
+	type description PubspecLockDescription
+	return node.Decode((*description)(d))
+}
+
+// ParsePubspecLock parses a pubspec.lock.
+func ParsePubspecLock(r io.Reader) (PubspecLock, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Dependency  PubspecLockDependencyKind `yaml:"dependency"`
+			Source      PubspecDependencySource   `yaml:"source"`
+			Version     string                    `yaml:"version"`
+			Description PubspecLockDescription    `yaml:"description"`
+		} `yaml:"packages"`
+		SDKs PubspecLockSDKs `yaml:"sdks"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&lock); err != nil {
+		return PubspecLock{}, err
+	}
+
+	pubspecLock := PubspecLock{SDKs: lock.SDKs}
+	for name, pkg := range lock.Packages {
+		pubspecLock.Packages = append(pubspecLock.Packages, PubspecLockPackage{
+			Name:        name,
+			Dependency:  pkg.Dependency,
+			Source:      pkg.Source,
+			Version:     pkg.Version,
+			Description: pkg.Description,
+		})
+	}
+	sort.Slice(pubspecLock.Packages, func(i, j int) bool {
+		return pubspecLock.Packages[i].Name < pubspecLock.Packages[j].Name
+	})
+
+	return pubspecLock, nil
+}
+
+// Package returns the named resolved package.
+func (l PubspecLock) Package(name string) (PubspecLockPackage, bool) {
+	idx := sort.Search(len(l.Packages), func(i int) bool {
+		return l.Packages[i].Name >= name
+	})
+	if idx < len(l.Packages) && l.Packages[idx].Name == name {
+		return l.Packages[idx], true
+	}
+	return PubspecLockPackage{}, false
+}
+
+// PubspecLock returns the project's resolved packages, or nil if the project has no pubspec.lock.
+func (p *Project) PubspecLock() (*PubspecLock, error) {
+	pubspecLockPth := filepath.Join(p.rootDir, sdk.PubspecLockRelPath)
+	f, err := p.fileManager.OpenReaderIfExists(pubspecLockPth)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, nil
+	}
+
+	lock, err := ParsePubspecLock(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pubspecLockPth, err)
+	}
+	return &lock, nil
+}
@@ -0,0 +1,158 @@
+package flutterproject
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-flutter/flutterproject/internal/testassets"
+	"github.com/bitrise-io/go-flutter/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePubspecLock(t *testing.T) {
+	pubspecLock := `packages:
+  core:
+    dependency: "direct main"
+    description:
+      path: "../core"
+      relative: true
+    source: path
+    version: "1.0.0"
+  flutter:
+    dependency: "direct main"
+    description: flutter
+    source: sdk
+    version: "0.0.0"
+  http:
+    dependency: "direct overridden"
+    description:
+      name: http
+      sha256: "759d1a329847dd0f39226c688d3e06a6b8679668e350e2891a6474f8b4bb8247"
+      url: "https://pub.dev"
+    source: hosted
+    version: "1.1.2"
+  lints:
+    dependency: "direct dev"
+    description:
+      path: "packages/lints"
+      ref: main
+      resolved-ref: "6fd4cd9c5c5f3b6b6e0d29bf3ba8c26b2e8a1c5d"
+      url: "https://github.com/example/lints.git"
+    source: git
+    version: "2.1.0"
+  meta:
+    dependency: transitive
+    description:
+      name: meta
+      sha256: "3c74dbf8763d36539f114c799d8a2d87343b5067e9d796ca22b5eb8437090ee3"
+      url: "https://pub.dev"
+    source: hosted
+    version: "1.9.1"
+sdks:
+  dart: ">=3.0.0 <4.0.0"
+  flutter: ">=3.10.0"
+`
+	lock, err := ParsePubspecLock(strings.NewReader(pubspecLock))
+	require.NoError(t, err)
+
+	require.Equal(t, PubspecLock{
+		Packages: []PubspecLockPackage{
+			{
+				Name:        "core",
+				Dependency:  DirectMainDependency,
+				Source:      PathDependency,
+				Version:     "1.0.0",
+				Description: PubspecLockDescription{Path: "../core", Relative: true},
+			},
+			{
+				Name:        "flutter",
+				Dependency:  DirectMainDependency,
+				Source:      SDKDependency,
+				Version:     "0.0.0",
+				Description: PubspecLockDescription{SDK: "flutter"},
+			},
+			{
+				Name:        "http",
+				Dependency:  DirectOverriddenDependency,
+				Source:      HostedDependency,
+				Version:     "1.1.2",
+				Description: PubspecLockDescription{Name: "http", URL: "https://pub.dev", SHA256: "759d1a329847dd0f39226c688d3e06a6b8679668e350e2891a6474f8b4bb8247"},
+			},
+			{
+				Name:        "lints",
+				Dependency:  DirectDevDependency,
+				Source:      GitDependency,
+				Version:     "2.1.0",
+				Description: PubspecLockDescription{URL: "https://github.com/example/lints.git", Ref: "main", ResolvedRef: "6fd4cd9c5c5f3b6b6e0d29bf3ba8c26b2e8a1c5d", Path: "packages/lints"},
+			},
+			{
+				Name:        "meta",
+				Dependency:  TransitiveDependency,
+				Source:      HostedDependency,
+				Version:     "1.9.1",
+				Description: PubspecLockDescription{Name: "meta", URL: "https://pub.dev", SHA256: "3c74dbf8763d36539f114c799d8a2d87343b5067e9d796ca22b5eb8437090ee3"},
+			},
+		},
+		SDKs: PubspecLockSDKs{Dart: ">=3.0.0 <4.0.0", Flutter: ">=3.10.0"},
+	}, lock)
+
+	pkg, ok := lock.Package("lints")
+	require.True(t, ok)
+	require.Equal(t, "6fd4cd9c5c5f3b6b6e0d29bf3ba8c26b2e8a1c5d", pkg.Description.ResolvedRef)
+	_, ok = lock.Package("async")
+	require.False(t, ok)
+}
+
+func TestProject_PubspecLock(t *testing.T) {
+	tests := []struct {
+		name         string
+		pubspecLock  string
+		wantPackages int
+		wantErr      string
+	}{
+		{
+			name:         "Real pubspec.lock",
+			pubspecLock:  testassets.PubspecLock,
+			wantPackages: 24,
+		},
+		{
+			name: "No pubspec.lock",
+		},
+		{
+			name:        "Invalid pubspec.lock",
+			pubspecLock: "packages: []\n",
+			wantErr:     "failed to parse pubspec.lock: yaml: unmarshal errors:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileOpener := new(mocks.FileManager)
+			if tt.pubspecLock != "" {
+				fileOpener.On("OpenReaderIfExists", "pubspec.lock").Return(strings.NewReader(tt.pubspecLock), nil)
+			}
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			p := &Project{fileManager: fileOpener}
+			lock, err := p.PubspecLock()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.pubspecLock == "" {
+				require.Nil(t, lock)
+				return
+			}
+
+			require.Len(t, lock.Packages, tt.wantPackages)
+			pkg, ok := lock.Package("cupertino_icons")
+			require.True(t, ok)
+			require.Equal(t, DirectMainDependency, pkg.Dependency)
+			require.Equal(t, HostedDependency, pkg.Source)
+			require.Equal(t, "1.0.5", pkg.Version)
+			require.Equal(t, "e35129dc44c9118cee2a5603506d823bab99c68393879edb440e0090d07586be", pkg.Description.SHA256)
+			require.Equal(t, ">=2.19.6 <3.0.0", lock.SDKs.Dart)
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package flutterproject
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-flutter/flutterproject/internal/testassets"
+	"github.com/bitrise-io/go-utils/v2/fileutil"
+	"github.com/bitrise-io/go-utils/v2/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePubspec(t *testing.T) {
+	pubspecYaml := `name: my_plugin
+description: A Flutter plugin.
+version: 1.2.3+4
+publish_to: none
+homepage: https://example.com
+
+environment:
+  sdk: ">=3.0.0 <4.0.0"
+  flutter: ">=3.10.0"
+
+dependencies:
+  flutter:
+    sdk: flutter
+  http: ^1.1.0
+  any_version:
+  private_package:
+    hosted: https://pub.example.com
+    version: ^2.0.0
+  renamed_package:
+    hosted:
+      name: original_package
+      url: https://pub.example.com
+    version: ^3.0.0
+  core:
+    path: ../core
+
+dev_dependencies:
+  lints:
+    git: https://github.com/example/lints.git
+  tools:
+    git:
+      url: git@github.com:example/tools.git
+      ref: main
+      path: packages/tools
+    version: ^1.0.0
+
+dependency_overrides:
+  http: 1.1.2
+
+flutter:
+  uses-material-design: true
+  generate: true
+  assets:
+    - images/
+    - path: config/dev.json
+      flavors:
+        - dev
+  fonts:
+    - family: Raleway
+      fonts:
+        - asset: fonts/Raleway-Regular.ttf
+        - asset: fonts/Raleway-Italic.ttf
+          style: italic
+          weight: 700
+  plugin:
+    platforms:
+      android:
+        package: com.example.my_plugin
+        pluginClass: MyPlugin
+      ios:
+        pluginClass: MyPlugin
+        sharedDarwinSource: true
+      web:
+        pluginClass: MyPluginWeb
+        fileName: my_plugin_web.dart
+  shaders:
+    - shaders/blur.frag
+
+flutter_intl:
+  enabled: true
+`
+	pubspec, err := ParsePubspec(strings.NewReader(pubspecYaml), "pubspec.yaml")
+	require.NoError(t, err)
+
+	require.Equal(t, "my_plugin", pubspec.Name)
+	require.Equal(t, "A Flutter plugin.", pubspec.Description)
+	require.Equal(t, "1.2.3+4", pubspec.Version)
+	require.Equal(t, "none", pubspec.PublishTo)
+	require.Equal(t, PubspecEnvironment{SDK: ">=3.0.0 <4.0.0", Flutter: ">=3.10.0"}, pubspec.Environment)
+
+	require.Equal(t, []PubspecDependency{
+		{Name: "flutter", Source: SDKDependency, SDK: "flutter", Position: Position{File: "pubspec.yaml", Line: 12, Column: 3}},
+		{Name: "http", Source: HostedDependency, Version: "^1.1.0", Position: Position{File: "pubspec.yaml", Line: 14, Column: 3}},
+		{Name: "any_version", Source: HostedDependency, Position: Position{File: "pubspec.yaml", Line: 15, Column: 3}},
+		{Name: "private_package", Source: HostedDependency, Version: "^2.0.0", HostedURL: "https://pub.example.com", Position: Position{File: "pubspec.yaml", Line: 16, Column: 3}},
+		{Name: "renamed_package", Source: HostedDependency, Version: "^3.0.0", HostedName: "original_package", HostedURL: "https://pub.example.com", Position: Position{File: "pubspec.yaml", Line: 19, Column: 3}},
+		{Name: "core", Source: PathDependency, Path: "../core", Position: Position{File: "pubspec.yaml", Line: 24, Column: 3}},
+	}, pubspec.Dependencies)
+	require.Equal(t, []PubspecDependency{
+		{Name: "lints", Source: GitDependency, Git: &PubspecGitDependency{URL: "https://github.com/example/lints.git"}, Position: Position{File: "pubspec.yaml", Line: 28, Column: 3}},
+		{Name: "tools", Source: GitDependency, Version: "^1.0.0", Git: &PubspecGitDependency{URL: "git@github.com:example/tools.git", Ref: "main", Path: "packages/tools"}, Position: Position{File: "pubspec.yaml", Line: 30, Column: 3}},
+	}, pubspec.DevDependencies)
+
+	dependency, ok := pubspec.Dependency("http")
+	require.True(t, ok)
+	require.Equal(t, "1.1.2", dependency.Version)
+	_, ok = pubspec.Dependency("unknown")
+	require.False(t, ok)
+
+	require.NotNil(t, pubspec.Flutter)
+	require.True(t, pubspec.Flutter.UsesMaterialDesign)
+	require.True(t, pubspec.Flutter.Generate)
+	require.Equal(t, []PubspecAsset{
+		{Path: "images/", Position: Position{File: "pubspec.yaml", Line: 44, Column: 7}},
+		{Path: "config/dev.json", Flavors: []string{"dev"}, Position: Position{File: "pubspec.yaml", Line: 45, Column: 7}},
+	}, pubspec.Flutter.Assets)
+	require.Equal(t, []PubspecFontFamily{{Family: "Raleway", Fonts: []PubspecFont{
+		{Asset: "fonts/Raleway-Regular.ttf"},
+		{Asset: "fonts/Raleway-Italic.ttf", Style: "italic", Weight: 700},
+	}}}, pubspec.Flutter.Fonts)
+	require.Equal(t, map[string]PubspecPluginPlatform{
+		"android": {Package: "com.example.my_plugin", PluginClass: "MyPlugin"},
+		"ios":     {PluginClass: "MyPlugin", SharedDarwinSource: true},
+		"web":     {PluginClass: "MyPluginWeb", FileName: "my_plugin_web.dart"},
+	}, pubspec.Flutter.Plugin.Platforms)
+
+	require.Len(t, pubspec.Flutter.Extra, 1)
+	require.Equal(t, "shaders/blur.frag", pubspec.Flutter.Extra["shaders"].Content[0].Value)
+	require.Len(t, pubspec.Extra, 2)
+	require.Equal(t, "https://example.com", pubspec.Extra["homepage"].Value)
+	var flutterIntl struct {
+		Enabled bool `yaml:"enabled"`
+	}
+	require.NoError(t, pubspec.Extra["flutter_intl"].Decode(&flutterIntl))
+	require.True(t, flutterIntl.Enabled)
+}
+
+func TestParsePubspec_Extra(t *testing.T) {
+	pubspecYaml := `name: app
+environment:
+  sdk: ^3.0.0
+  ios: ">=12.0"
+dependencies:
+  http: ^1.1.0
+  core:
+    path: ../core
+    unknown: true
+  tools:
+    git:
+      url: https://github.com/example/tools.git
+      depth: 1
+flutter:
+  assets:
+    - path: images/
+      transformers:
+        - package: vector_graphics_compiler
+  fonts:
+    - family: Raleway
+      display: swap
+      fonts:
+        - asset: fonts/Raleway-Regular.ttf
+          variable: true
+  plugin:
+    implements: app_platform_interface
+    platforms:
+      android:
+        package: com.example.app
+        pluginClass: AppPlugin
+        minSdk: 21
+`
+	pubspec, err := ParsePubspec(strings.NewReader(pubspecYaml), "pubspec.yaml")
+	require.NoError(t, err)
+
+	require.Equal(t, "^3.0.0", pubspec.Environment.SDK)
+	require.Len(t, pubspec.Environment.Extra, 1)
+	require.Equal(t, ">=12.0", pubspec.Environment.Extra["ios"].Value)
+
+	http, ok := pubspec.Dependency("http")
+	require.True(t, ok)
+	require.Nil(t, http.Extra)
+	core, ok := pubspec.Dependency("core")
+	require.True(t, ok)
+	require.Equal(t, "../core", core.Path)
+	require.Len(t, core.Extra, 1)
+	require.Equal(t, "true", core.Extra["unknown"].Value)
+	tools, ok := pubspec.Dependency("tools")
+	require.True(t, ok)
+	require.Equal(t, "1", tools.Git.Extra["depth"].Value)
+
+	require.Equal(t, "vector_graphics_compiler", pubspec.Flutter.Assets[0].Extra["transformers"].Content[0].Content[1].Value)
+	require.Equal(t, "swap", pubspec.Flutter.Fonts[0].Extra["display"].Value)
+	require.Equal(t, "true", pubspec.Flutter.Fonts[0].Fonts[0].Extra["variable"].Value)
+	require.Equal(t, "app_platform_interface", pubspec.Flutter.Plugin.Extra["implements"].Value)
+	android := pubspec.Flutter.Plugin.Platforms["android"]
+	require.Equal(t, "AppPlugin", android.PluginClass)
+	require.Len(t, android.Extra, 1)
+	require.Equal(t, "21", android.Extra["minSdk"].Value)
+}
+
+func TestProject_PubspecModel(t *testing.T) {
+	rootDir := t.TempDir()
+	pubspecYaml := "name: app\ndev_dependencies:\n  lints:\n    - ^2.0.0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "pubspec.yaml"), []byte(pubspecYaml), 0600))
+
+	project, err := New(rootDir, fileutil.NewFileManager(), pathutil.NewPathChecker(), nil)
+	require.NoError(t, err)
+	require.Equal(t, PubspecInfo{Name: "app"}, project.Pubspec())
+
+	_, err = project.PubspecModel()
+	require.ErrorContains(t, err, "pubspec.yaml:4:5: invalid dependency lints: not a version constraint or a map")
+
+	pubspecYaml = "name: app\nversion: 1.0.0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "pubspec.yaml"), []byte(pubspecYaml), 0600))
+	pubspec, err := project.PubspecModel()
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", pubspec.Version)
+}
+
+func TestPubspec_Position(t *testing.T) {
+	pubspec, err := ParsePubspec(strings.NewReader(testassets.PubspecYaml), "app/pubspec.yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		path     []string
+		want     string
+		wantNone bool
+	}{
+		{name: "Document", want: "app/pubspec.yaml:1:1"},
+		{name: "Top level key", path: []string{"version"}, want: "app/pubspec.yaml:19:1"},
+		{name: "Nested key", path: []string{"environment", "flutter"}, want: "app/pubspec.yaml:23:3"},
+		{name: "Dependency", path: []string{"dev_dependencies", "flutter_lints"}, want: "app/pubspec.yaml:49:3"},
+		{name: "Unknown key", path: []string{"environment", "unknown"}, wantNone: true},
+		{name: "Index of a map", path: []string{"environment", "0"}, wantNone: true},
+		{name: "Key of a scalar", path: []string{"version", "major"}, wantNone: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pubspec.Position(tt.path...)
+			if tt.wantNone {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tt.want, got.String())
+		})
+	}
+
+	sequence, err := ParsePubspec(strings.NewReader("flutter:\n  assets:\n    - images/\n    - fonts/\n"), "pubspec.yaml")
+	require.NoError(t, err)
+	got, ok := sequence.Position("flutter", "assets", "1")
+	require.True(t, ok)
+	require.Equal(t, "pubspec.yaml:4:7", got.String())
+	_, ok = sequence.Position("flutter", "assets", "2")
+	require.False(t, ok)
+}
+
+func TestParsePubspec_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pubspec string
+		wantErr string
+	}{
+		{
+			name:    "Not a map",
+			pubspec: "- name: app\n",
+			wantErr: "pubspec.yaml:1:1: pubspec.yaml is not a map",
+		},
+		{
+			name:    "Dependency with more than one source",
+			pubspec: "name: app\ndependencies:\n  core:\n    path: ../core\n    git: https://github.com/example/core.git\n",
+			wantErr: "pubspec.yaml:4:5: invalid dependency core: more than one source: git and path",
+		},
+		{
+			name:    "Dependency list",
+			pubspec: "name: app\ndev_dependencies:\n  lints:\n    - ^2.0.0\n",
+			wantErr: "pubspec.yaml:4:5: invalid dependency lints: not a version constraint or a map",
+		},
+		{
+			name:    "Dependencies are not a map",
+			pubspec: "name: app\ndependencies: http\n",
+			wantErr: "pubspec.yaml:2:15: invalid dependencies: not a map",
+		},
+		{
+			name:    "Invalid flutter section",
+			pubspec: "name: app\nflutter:\n  assets: images/\n",
+			wantErr: "pubspec.yaml:3:3: invalid flutter: yaml: unmarshal errors:\n  line 3: cannot unmarshal !!str `images/` into []flutterproject.PubspecAsset",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePubspec(strings.NewReader(tt.pubspec), "pubspec.yaml")
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
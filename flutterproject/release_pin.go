@@ -0,0 +1,117 @@
+package flutterproject
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/bitrise-io/go-flutter/fluttersdk"
+)
+
+// ReleasePinRelPath is the project relative path of the file, which records the Flutter SDK release picked at the first resolution.
+const ReleasePinRelPath = ".flutter-sdk-pin.json"
+
+// ReleasePin is the Flutter SDK release recorded by a project created with WithReleasePin.
+type ReleasePin struct {
+	FlutterVersion string `json:"flutter_version"`
+	FlutterChannel string `json:"flutter_channel"`
+	FlutterCommit  string `json:"flutter_commit"`
+	DartVersion    string `json:"dart_version,omitempty"`
+}
+
+// WithResolutionStrategy returns a copy of the project, which picks the Flutter SDK release with the given strategy
+// if the project's requirements are version constraints. The installed versions are used by the fluttersdk.PreferInstalled strategy.
+func (p *Project) WithResolutionStrategy(strategy fluttersdk.ResolutionStrategy, installedFlutterVersions ...*semver.Version) *Project {
+	project := *p
+	project.resolutionStrategy = strategy
+	project.installedFlutterVersions = installedFlutterVersions
+	return &project
+}
+
+/*
+WithReleasePin returns a copy of the project, which records the Flutter SDK release picked at the first resolution
+in the project's ReleasePinRelPath file, so later resolutions don't drift to newer releases.
+
+The recorded release is used as long as it satisfies the project's SDK requirements,
+otherwise the release is resolved again and the new choice is recorded.
+*/
+func (p *Project) WithReleasePin() *Project {
+	project := *p
+	project.pinRelease = true
+	return &project
+}
+
+// ReleasePin returns the Flutter SDK release recorded in the project's ReleasePinRelPath file, or nil if there is no recorded release.
+func (p *Project) ReleasePin() (*ReleasePin, error) {
+	pinPth := filepath.Join(p.rootDir, ReleasePinRelPath)
+	f, err := p.fileManager.OpenReaderIfExists(pinPth)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, nil
+	}
+
+	var pin ReleasePin
+	if err := json.NewDecoder(f).Decode(&pin); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pinPth, err)
+	}
+	return &pin, nil
+}
+
+func (p *Project) writeReleasePin(release fluttersdk.Release) error {
+	pin := ReleasePin{
+		FlutterVersion: release.Version,
+		FlutterChannel: release.Channel,
+		FlutterCommit:  release.Hash,
+		DartVersion:    release.DartSdkVersion,
+	}
+
+	b, err := json.MarshalIndent(pin, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	pinPth := filepath.Join(p.rootDir, ReleasePinRelPath)
+	if err := p.fileManager.WriteBytes(pinPth, append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %w", pinPth, err)
+	}
+	return nil
+}
+
+// pinnedReleaseFor returns the recorded release, or nil if there is no recorded release or it doesn't satisfy the resolved requirements.
+func (p *Project) pinnedReleaseFor(platform fluttersdk.Platform, architecture fluttersdk.Architecture, resolution SDKResolution) (*fluttersdk.Release, error) {
+	pin, err := p.ReleasePin()
+	if err != nil {
+		return nil, err
+	}
+	if pin == nil {
+		return nil, nil
+	}
+
+	if resolution.FlutterRequirement == FlutterRequirementCommit && !strings.EqualFold(pin.FlutterCommit, resolution.FlutterCommit) {
+		return nil, nil
+	}
+
+	version, err := semver.NewVersion(pin.FlutterVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Flutter version (%s) in %s: %w", pin.FlutterVersion, ReleasePinRelPath, err)
+	}
+
+	release, err := p.sdkVersionFinder.FindLatestReleaseFor(platform, architecture, fluttersdk.Channel(pin.FlutterChannel), fluttersdk.SDKQuery{FlutterVersion: version})
+	if err != nil {
+		var noMatchingReleaseErr fluttersdk.NoMatchingReleaseError
+		if errors.As(err, &noMatchingReleaseErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if release == nil || !strings.EqualFold(release.Hash, pin.FlutterCommit) {
+		return nil, nil
+	}
+
+	return matchingRelease(release, resolution)
+}
@@ -0,0 +1,120 @@
+package flutterproject
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-flutter/fluttersdk"
+	"github.com/bitrise-io/go-flutter/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProject_FlutterSDKReleaseToUse_ReleasePin(t *testing.T) {
+	tests := []struct {
+		name        string
+		pin         string
+		pubspec     string
+		wantVersion string
+		wantPin     string
+	}{
+		{
+			name:        "Records the first resolution",
+			pubspec:     "environment:\n  flutter: \">=3.13.0\"",
+			wantVersion: "3.16.0",
+			wantPin: `{
+  "flutter_version": "3.16.0",
+  "flutter_channel": "stable",
+  "flutter_commit": "db7ef5bf9f59442b0e200a90587e8fa5e0c6336a",
+  "dart_version": "3.2.0"
+}
+`,
+		},
+		{
+			name:        "Recorded release is reused",
+			pin:         `{"flutter_version": "3.13.8", "flutter_channel": "stable", "flutter_commit": "6c4930c4ac86fb286f30e31d0ec8bffbcbb9953e"}`,
+			pubspec:     "environment:\n  flutter: \">=3.13.0\"",
+			wantVersion: "3.13.8",
+		},
+		{
+			name:        "Recorded release doesn't satisfy the requirements anymore",
+			pin:         `{"flutter_version": "3.13.8", "flutter_channel": "stable", "flutter_commit": "6c4930c4ac86fb286f30e31d0ec8bffbcbb9953e"}`,
+			pubspec:     "environment:\n  flutter: \">=3.13.9\"",
+			wantVersion: "3.16.0",
+			wantPin: `{
+  "flutter_version": "3.16.0",
+  "flutter_channel": "stable",
+  "flutter_commit": "db7ef5bf9f59442b0e200a90587e8fa5e0c6336a",
+  "dart_version": "3.2.0"
+}
+`,
+		},
+		{
+			name:        "Recorded release was rebuilt from another commit",
+			pin:         `{"flutter_version": "3.13.8", "flutter_channel": "stable", "flutter_commit": "0000000000000000000000000000000000000000"}`,
+			pubspec:     "environment:\n  sdk: \"<3.2.0\"",
+			wantVersion: "3.13.9",
+			wantPin: `{
+  "flutter_version": "3.13.9",
+  "flutter_channel": "stable",
+  "flutter_commit": "d211f42860350d914a5ad8102f9ec32764dc6d06",
+  "dart_version": "3.1.5"
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availableSDKLister := new(mocks.SDKVersionLister)
+			availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]fluttersdk.Release{"stable": {
+				{Channel: "stable", Version: "3.16.0", DartSdkVersion: "3.2.0", Hash: "db7ef5bf9f59442b0e200a90587e8fa5e0c6336a"},
+				{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5", Hash: "d211f42860350d914a5ad8102f9ec32764dc6d06"},
+				{Channel: "stable", Version: "3.13.8", DartSdkVersion: "3.1.4", Hash: "6c4930c4ac86fb286f30e31d0ec8bffbcbb9953e"},
+			}}, nil)
+
+			fileOpener := new(mocks.FileManager)
+			if tt.pin != "" {
+				fileOpener.On("OpenReaderIfExists", ReleasePinRelPath).Return(func(string) io.Reader { return strings.NewReader(tt.pin) }, nil)
+			}
+			fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(func(string) io.Reader { return strings.NewReader(tt.pubspec) }, nil)
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			var gotPin string
+			fileOpener.On("WriteBytes", ReleasePinRelPath, mock.Anything).Run(func(args mock.Arguments) {
+				gotPin = string(args.Get(1).([]byte))
+			}).Return(nil)
+
+			p := &Project{
+				fileManager:      fileOpener,
+				sdkVersionFinder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister},
+			}
+			release, err := p.WithReleasePin().FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantVersion, release.Version)
+			require.Equal(t, tt.wantPin, gotPin)
+		})
+	}
+}
+
+func TestProject_FlutterSDKReleaseToUse_ReleasePinWithoutRelease(t *testing.T) {
+	fileOpener := new(mocks.FileManager)
+	fileOpener.On("OpenReaderIfExists", ReleasePinRelPath).Return(strings.NewReader(`{"flutter_version": "3.13.8", "flutter_channel": "stable", "flutter_commit": "6c4930c4ac86fb286f30e31d0ec8bffbcbb9953e"}`), nil)
+	fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+	p := &Project{fileManager: fileOpener, sdkVersionFinder: nilReleaseFinder{}}
+	_, err := p.WithReleasePin().FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+	var noMatchingReleaseErr fluttersdk.NoMatchingReleaseError
+	require.ErrorAs(t, err, &noMatchingReleaseErr)
+	fileOpener.AssertNotCalled(t, "WriteBytes", mock.Anything, mock.Anything)
+}
+
+func TestProject_ReleasePin(t *testing.T) {
+	fileOpener := new(mocks.FileManager)
+	fileOpener.On("OpenReaderIfExists", ReleasePinRelPath).Return(strings.NewReader(`{"flutter_version": "3.13.8"`), nil)
+
+	p := &Project{fileManager: fileOpener}
+	pin, err := p.ReleasePin()
+	require.EqualError(t, err, "failed to parse .flutter-sdk-pin.json: unexpected EOF")
+	require.Nil(t, pin)
+}
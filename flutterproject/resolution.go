@@ -0,0 +1,471 @@
+package flutterproject
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/bitrise-io/go-flutter/flutterproject/internal/sdk"
+	"github.com/bitrise-io/go-flutter/fluttersdk"
+)
+
+type SDKVersionSourceStatus string
+
+const (
+	SDKVersionSourceSelected SDKVersionSourceStatus = "selected"
+	SDKVersionSourceIgnored  SDKVersionSourceStatus = "ignored"
+	SDKVersionSourceNotFound SDKVersionSourceStatus = "not_found"
+	// SDKVersionSourceCombined means the source's constraint was intersected with the selected source's requirement.
+	SDKVersionSourceCombined SDKVersionSourceStatus = "combined"
+	// SDKVersionSourceConflicting means the source's constraint excludes every version allowed by the higher precedence sources.
+	SDKVersionSourceConflicting SDKVersionSourceStatus = "conflicting"
+)
+
+// FlutterRequirementKind tells how the selected source pins the Flutter SDK.
+type FlutterRequirementKind string
+
+const (
+	FlutterRequirementNone        FlutterRequirementKind = ""
+	FlutterRequirementVersion     FlutterRequirementKind = "version"
+	FlutterRequirementConstraint  FlutterRequirementKind = "constraint"
+	FlutterRequirementChannelHead FlutterRequirementKind = "channel_head"
+	FlutterRequirementCommit      FlutterRequirementKind = "commit"
+	// FlutterRequirementPreferredCommit means no source requires a Flutter version, the release of the .metadata revision
+	// is preferred as long as it satisfies the Dart SDK requirements, the latest release is used otherwise.
+	FlutterRequirementPreferredCommit FlutterRequirementKind = "preferred_commit"
+)
+
+// FlutterConstraintMode tells how the selected Flutter version requirement is applied.
+type FlutterConstraintMode string
+
+const (
+	// FlutterConstraintAsWritten means the requirement is applied as written in the source.
+	FlutterConstraintAsWritten FlutterConstraintMode = "as_written"
+	// FlutterConstraintMinimumOnly means only the lower bound of the requirement is applied,
+	// like Flutter does with pubspec.yaml's environment.flutter.
+	FlutterConstraintMinimumOnly FlutterConstraintMode = "minimum_only"
+)
+
+// SDKRequirementConflictError is returned if the SDK requirements of the project's sources have no version in common.
+// Requirement is the intersection of the higher precedence sources' requirements, which Source's Value excludes completely.
+type SDKRequirementConflictError struct {
+	SDK         string `json:"sdk"`
+	Source      string `json:"source"`
+	File        string `json:"file"`
+	Value       string `json:"value"`
+	Requirement string `json:"requirement"`
+}
+
+func (e SDKRequirementConflictError) Error() string {
+	return fmt.Sprintf("no %s version satisfies every SDK requirement: %s (%s) requires %s, which doesn't allow any version of %s required by the higher precedence sources",
+		e.SDK, e.Source, e.File, e.Value, e.Requirement)
+}
+
+// SDKVersionSourceResult describes what a single SDK version source provided and whether it was used for the resolution.
+// Value is the requirement as written in the source, EffectiveValue is set if a different requirement is applied.
+type SDKVersionSourceResult struct {
+	Source         string                 `json:"source"`
+	File           string                 `json:"file"`
+	SDK            string                 `json:"sdk"`
+	Value          string                 `json:"value,omitempty"`
+	EffectiveValue string                 `json:"effective_value,omitempty"`
+	Channel        string                 `json:"channel,omitempty"`
+	Status         SDKVersionSourceStatus `json:"status"`
+	Reason         string                 `json:"reason"`
+}
+
+/*
+SDKResolution is the explainable result of picking the Flutter and Dart SDK requirements from the project's SDK version sources.
+
+FlutterVersion and FlutterVersionConstraint are the applied Flutter requirement, FlutterConstraintMode tells how it was derived
+from the selected source's requirement. RawFlutterRequirement is the requirement as written, if it differs from the applied one.
+
+If the selected requirement is a constraint, it is intersected with the constraints of the lower precedence sources,
+Dart versions are intersected with the other Dart requirements too. Conflicts lists the sources which made an intersection empty,
+no release satisfies the requirements in that case.
+*/
+type SDKResolution struct {
+	FlutterRequirement       FlutterRequirementKind        `json:"flutter_requirement,omitempty"`
+	FlutterConstraintMode    FlutterConstraintMode         `json:"flutter_constraint_mode,omitempty"`
+	RawFlutterRequirement    string                        `json:"raw_flutter_requirement,omitempty"`
+	FlutterVersion           string                        `json:"flutter_version,omitempty"`
+	FlutterVersionConstraint string                        `json:"flutter_version_constraint,omitempty"`
+	FlutterChannel           string                        `json:"flutter_channel,omitempty"`
+	FlutterCommit            string                        `json:"flutter_commit,omitempty"`
+	DartVersion              string                        `json:"dart_version,omitempty"`
+	DartVersionConstraint    string                        `json:"dart_version_constraint,omitempty"`
+	Sources                  []SDKVersionSourceResult      `json:"sources"`
+	Conflicts                []SDKRequirementConflictError `json:"conflicts,omitempty"`
+
+	Query fluttersdk.SDKQuery `json:"-"`
+}
+
+func (r SDKResolution) String() string {
+	var b strings.Builder
+
+	var flutterRequirement string
+	switch r.FlutterRequirement {
+	case FlutterRequirementChannelHead:
+		flutterRequirement = fmt.Sprintf("head of the %s channel", r.FlutterChannel)
+	case FlutterRequirementCommit:
+		flutterRequirement = fmt.Sprintf("commit %s", r.FlutterCommit)
+	case FlutterRequirementPreferredCommit:
+		flutterRequirement = fmt.Sprintf("any (commit %s preferred)", r.FlutterCommit)
+	default:
+		flutterRequirement = formatRequirement(r.FlutterVersion, r.FlutterVersionConstraint)
+	}
+	if r.FlutterConstraintMode == FlutterConstraintMinimumOnly {
+		flutterRequirement += fmt.Sprintf(" (minimum of %s, the upper bound is ignored)", r.RawFlutterRequirement)
+	}
+	if r.FlutterChannel != "" && r.FlutterRequirement != FlutterRequirementChannelHead {
+		flutterRequirement += fmt.Sprintf(" (channel: %s)", r.FlutterChannel)
+	}
+	b.WriteString(fmt.Sprintf("Flutter SDK: %s\n", flutterRequirement))
+	writeSourceResults(&b, r.Sources, flutterSDK)
+
+	b.WriteString(fmt.Sprintf("Dart SDK: %s\n", formatRequirement(r.DartVersion, r.DartVersionConstraint)))
+	writeSourceResults(&b, r.Sources, dartSDK)
+
+	return b.String()
+}
+
+func formatRequirement(version, constraint string) string {
+	switch {
+	case version != "":
+		return version
+	case constraint != "":
+		return constraint
+	default:
+		return "any (no requirement found)"
+	}
+}
+
+func writeSourceResults(b *strings.Builder, results []SDKVersionSourceResult, sdkName string) {
+	for _, result := range results {
+		if result.SDK != sdkName {
+			continue
+		}
+
+		value := result.Value
+		if result.EffectiveValue != "" {
+			value += fmt.Sprintf(" (applied as %s)", result.EffectiveValue)
+		}
+		if result.Channel != "" {
+			value += fmt.Sprintf(" (channel: %s)", result.Channel)
+		}
+		if value != "" {
+			value = ": " + value
+		}
+
+		b.WriteString(fmt.Sprintf("  [%s] %s (%s)%s - %s\n", result.Status, result.Source, result.File, value, result.Reason))
+	}
+}
+
+const (
+	flutterSDK = "flutter"
+	dartSDK    = "dart"
+)
+
+// sdkVersionCandidate is an SDK requirement read from a single source.
+type sdkVersionCandidate struct {
+	source     string
+	file       string
+	version    *semver.Version
+	constraint fluttersdk.VersionConstraint
+	channel    string
+	commit     string
+	// rawValue is the requirement as written in the source, if the applied version or constraint differs from it.
+	rawValue string
+	// preferred is set for the .metadata revision, which is not a requirement, only a preference.
+	preferred bool
+}
+
+func (c sdkVersionCandidate) found() bool {
+	return c.requirement() != FlutterRequirementNone
+}
+
+func (c sdkVersionCandidate) requirement() FlutterRequirementKind {
+	switch {
+	case c.version != nil:
+		return FlutterRequirementVersion
+	case c.constraint != nil:
+		return FlutterRequirementConstraint
+	case c.commit != "" && c.preferred:
+		return FlutterRequirementPreferredCommit
+	case c.commit != "":
+		return FlutterRequirementCommit
+	case c.channel != "":
+		return FlutterRequirementChannelHead
+	default:
+		return FlutterRequirementNone
+	}
+}
+
+func (c sdkVersionCandidate) value() string {
+	switch c.requirement() {
+	case FlutterRequirementVersion:
+		return c.version.String()
+	case FlutterRequirementConstraint:
+		return c.constraint.String()
+	case FlutterRequirementCommit, FlutterRequirementPreferredCommit:
+		return c.commit
+	case FlutterRequirementChannelHead:
+		return c.channel
+	default:
+		return ""
+	}
+}
+
+// writtenValue returns the requirement as written in the source.
+func (c sdkVersionCandidate) writtenValue() string {
+	if c.rawValue != "" {
+		return c.rawValue
+	}
+	return c.value()
+}
+
+// qualifiedChannel returns the channel the candidate's version is qualified with, channel heads have no qualifier.
+func (c sdkVersionCandidate) qualifiedChannel() string {
+	if c.requirement() == FlutterRequirementChannelHead {
+		return ""
+	}
+	return c.channel
+}
+
+// flutterVersionCandidates returns the Flutter SDK requirements in precedence order,
+// with the effective constraint applied instead of the written requirement, if the source has one.
+func flutterVersionCandidates(results []VersionSourceResult) []sdkVersionCandidate {
+	var candidates []sdkVersionCandidate
+	for _, result := range results {
+		candidate := sdkVersionCandidate{
+			source:     result.Source,
+			file:       result.File,
+			version:    result.FlutterVersion,
+			constraint: result.FlutterVersionConstraint,
+			channel:    result.FlutterChannel,
+			commit:     result.FlutterCommit,
+			preferred:  result.Source == MetadataVersionSourceName,
+		}
+		if result.EffectiveFlutterVersionConstraint != nil {
+			candidate.rawValue = candidate.value()
+			candidate.version = nil
+			candidate.constraint = result.EffectiveFlutterVersionConstraint
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// dartVersionCandidates returns the Dart SDK requirements in precedence order.
+// Sources which never define Dart SDK requirements (like version managers) are skipped.
+func dartVersionCandidates(results []VersionSourceResult) []sdkVersionCandidate {
+	var candidates []sdkVersionCandidate
+	for _, result := range results {
+		if !definesDartSDKVersion(result.Source) {
+			continue
+		}
+		candidates = append(candidates, sdkVersionCandidate{
+			source:     result.Source,
+			file:       result.File,
+			version:    result.DartVersion,
+			constraint: result.DartVersionConstraint,
+		})
+	}
+	return candidates
+}
+
+// definesDartSDKVersion tells whether the source might define a Dart SDK requirement,
+// the built-in Flutter version manager and CI config sources never do.
+func definesDartSDKVersion(source string) bool {
+	switch source {
+	case FVMVersionSourceName, ASDFVersionSourceName, MiseVersionSourceName, PuroVersionSourceName, ProtoVersionSourceName, MetadataVersionSourceName,
+		GitHubActionsVersionSourceName, CodemagicVersionSourceName, BitriseVersionSourceName:
+		return false
+	default:
+		return true
+	}
+}
+
+// selectCandidate picks the first found candidate and explains the decision for every candidate,
+// preferred candidates are only picked if none of the candidates has a requirement.
+func selectCandidate(sdkName string, candidates []sdkVersionCandidate) (*sdkVersionCandidate, []SDKVersionSourceResult) {
+	var selected *sdkVersionCandidate
+	var results []SDKVersionSourceResult
+
+	required := false
+	for _, candidate := range candidates {
+		if candidate.found() && !candidate.preferred {
+			required = true
+		}
+	}
+
+	for i, candidate := range candidates {
+		result := SDKVersionSourceResult{
+			Source:  candidate.source,
+			File:    candidate.file,
+			SDK:     sdkName,
+			Value:   candidate.writtenValue(),
+			Channel: candidate.qualifiedChannel(),
+		}
+		if candidate.rawValue != "" {
+			result.EffectiveValue = candidate.value()
+		}
+
+		switch {
+		case !candidate.found():
+			result.Status = SDKVersionSourceNotFound
+			result.Reason = fmt.Sprintf("no %s version found", sdkName)
+		case candidate.preferred && required:
+			result.Status = SDKVersionSourceIgnored
+			result.Reason = fmt.Sprintf("only preferred if no other source requires a %s version", sdkName)
+		case selected == nil && candidate.preferred:
+			selected = &candidates[i]
+			result.Status = SDKVersionSourceSelected
+			result.Reason = "preferred, because no other source requires a version"
+		case selected == nil:
+			selected = &candidates[i]
+			result.Status = SDKVersionSourceSelected
+			result.Reason = "highest precedence source with a version"
+		default:
+			result.Status = SDKVersionSourceIgnored
+			result.Reason = fmt.Sprintf("overridden by %s, which has higher precedence", selected.source)
+		}
+
+		results = append(results, result)
+	}
+
+	return selected, results
+}
+
+/*
+combineCandidates intersects the selected candidate's requirement with the constraints of the lower precedence candidates
+and updates their results. Exact versions are only combined if combineVersions is set, pins of other kinds are never combined.
+Custom sources' constraints, which are not pub constraints, can't be intersected, the selected one is kept as is.
+Returns the combined requirement (nil if nothing was combined) and the conflict, if a candidate made the intersection empty.
+*/
+func combineCandidates(sdkName string, selected *sdkVersionCandidate, candidates []sdkVersionCandidate, results []SDKVersionSourceResult, combineVersions bool) (*sdk.Constraint, *SDKRequirementConflictError) {
+	combinable := func(candidate sdkVersionCandidate) *sdk.Constraint {
+		if candidate.requirement() == FlutterRequirementVersion && !combineVersions {
+			return nil
+		}
+		return toPubConstraint(candidate.version, candidate.constraint)
+	}
+
+	if selected == nil {
+		return nil, nil
+	}
+	combined := combinable(*selected)
+	if combined == nil {
+		return nil, nil
+	}
+
+	var conflict *SDKRequirementConflictError
+	combinedAny := false
+	for i, candidate := range candidates {
+		if results[i].Status != SDKVersionSourceIgnored {
+			continue
+		}
+		constraint := combinable(candidate)
+		if constraint == nil {
+			continue
+		}
+
+		intersection := intersectConstraints(combined, constraint)
+		if intersection.IsEmpty() && conflict == nil && !combined.IsEmpty() {
+			conflict = &SDKRequirementConflictError{SDK: sdkName, Source: candidate.source, File: candidate.file, Value: candidate.value(), Requirement: combined.String()}
+			results[i].Status = SDKVersionSourceConflicting
+			results[i].Reason = fmt.Sprintf("doesn't allow any version of %s required by the higher precedence sources", combined.String())
+		} else {
+			results[i].Status = SDKVersionSourceCombined
+			results[i].Reason = fmt.Sprintf("intersected with the requirement of %s", selected.source)
+		}
+		combined = intersection
+		combinedAny = true
+	}
+
+	if !combinedAny {
+		return nil, nil
+	}
+	return combined, conflict
+}
+
+// toPubConstraint returns the requirement as a pub constraint, or nil if it isn't one, like the constraints of custom sources.
+func toPubConstraint(version *semver.Version, constraint fluttersdk.VersionConstraint) *sdk.Constraint {
+	if version != nil {
+		return sdk.ExactVersionConstraint(version)
+	}
+	pubConstraint, _ := constraint.(*sdk.Constraint)
+	return pubConstraint
+}
+
+// intersectConstraints returns the intersection of the constraints, the first one (and its text) is kept if the other doesn't narrow it.
+func intersectConstraints(constraint, other *sdk.Constraint) *sdk.Constraint {
+	if constraint == nil {
+		return other
+	}
+	intersection := constraint.Intersect(other)
+	if intersection.Equal(constraint) {
+		return constraint
+	}
+	return intersection
+}
+
+func createSDKQuery(results []VersionSourceResult) SDKResolution {
+	resolution := SDKResolution{}
+
+	flutterCandidates := flutterVersionCandidates(results)
+	flutterCandidate, flutterResults := selectCandidate(flutterSDK, flutterCandidates)
+	flutterConstraint, flutterConflict := combineCandidates(flutterSDK, flutterCandidate, flutterCandidates, flutterResults, false)
+	resolution.Sources = append(resolution.Sources, flutterResults...)
+	if flutterConflict != nil {
+		resolution.Conflicts = append(resolution.Conflicts, *flutterConflict)
+	}
+	if flutterCandidate != nil {
+		resolution.FlutterRequirement = flutterCandidate.requirement()
+		switch {
+		case flutterCandidate.rawValue != "":
+			resolution.FlutterConstraintMode = FlutterConstraintMinimumOnly
+			resolution.RawFlutterRequirement = flutterCandidate.rawValue
+		case flutterCandidate.version != nil || flutterCandidate.constraint != nil:
+			resolution.FlutterConstraintMode = FlutterConstraintAsWritten
+		}
+		resolution.Query.FlutterVersion = flutterCandidate.version
+		resolution.Query.FlutterVersionConstraint = flutterCandidate.constraint
+		resolution.FlutterChannel = flutterCandidate.channel
+		resolution.FlutterCommit = flutterCandidate.commit
+		if flutterConstraint != nil {
+			resolution.Query.FlutterVersionConstraint = flutterConstraint
+		}
+	}
+
+	dartCandidates := dartVersionCandidates(results)
+	dartCandidate, dartResults := selectCandidate(dartSDK, dartCandidates)
+	dartConstraint, dartConflict := combineCandidates(dartSDK, dartCandidate, dartCandidates, dartResults, true)
+	resolution.Sources = append(resolution.Sources, dartResults...)
+	if dartConflict != nil {
+		resolution.Conflicts = append(resolution.Conflicts, *dartConflict)
+	}
+	if dartCandidate != nil {
+		resolution.Query.DartVersion = dartCandidate.version
+		resolution.Query.DartVersionConstraint = dartCandidate.constraint
+		if dartConstraint != nil {
+			resolution.Query.DartVersion = nil
+			resolution.Query.DartVersionConstraint = dartConstraint
+		}
+	}
+
+	if resolution.Query.FlutterVersion != nil {
+		resolution.FlutterVersion = resolution.Query.FlutterVersion.String()
+	}
+	if resolution.Query.FlutterVersionConstraint != nil {
+		resolution.FlutterVersionConstraint = resolution.Query.FlutterVersionConstraint.String()
+	}
+	if resolution.Query.DartVersion != nil {
+		resolution.DartVersion = resolution.Query.DartVersion.String()
+	}
+	if resolution.Query.DartVersionConstraint != nil {
+		resolution.DartVersionConstraint = resolution.Query.DartVersionConstraint.String()
+	}
+
+	return resolution
+}
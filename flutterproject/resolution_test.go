@@ -0,0 +1,277 @@
+package flutterproject
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/bitrise-io/go-flutter/flutterproject/internal/sdk"
+	"github.com/bitrise-io/go-flutter/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_createSDKQuery(t *testing.T) {
+	pubspecFlutterVersion, err := semver.NewConstraint("^3.7.12")
+	require.NoError(t, err)
+	pubspecDartVersion, err := semver.NewConstraint(">=2.19.6 <3.0.0")
+	require.NoError(t, err)
+
+	results := emptyVersionSourceResults()
+	results[1].FlutterVersion = semver.MustParse("3.7.12")
+	results[1].FlutterChannel = "stable"
+	results[6].FlutterVersionConstraint = pubspecFlutterVersion
+	results[6].DartVersionConstraint = pubspecDartVersion
+
+	resolution := createSDKQuery(results)
+
+	require.Equal(t, "3.7.12", resolution.Query.FlutterVersion.String())
+	require.Nil(t, resolution.Query.FlutterVersionConstraint)
+	require.Equal(t, ">=2.19.6 <3.0.0", resolution.Query.DartVersionConstraint.String())
+
+	require.Equal(t, `Flutter SDK: 3.7.12 (channel: stable)
+  [not_found] fvm (.fvmrc, .fvm/fvm_config.json, .fvm/version) - no flutter version found
+  [selected] asdf (.tool-versions): 3.7.12 (channel: stable) - highest precedence source with a version
+  [not_found] mise (.mise.local.toml, mise.local.toml, .mise.toml, .mise/config.toml, mise.toml, .config/mise.toml, .config/mise/config.toml, .rtx.toml) - no flutter version found
+  [not_found] puro (.puro.json) - no flutter version found
+  [not_found] proto (.prototools) - no flutter version found
+  [not_found] pubspec.lock (pubspec.lock) - no flutter version found
+  [ignored] pubspec.yaml (pubspec.yaml): ^3.7.12 - overridden by asdf, which has higher precedence
+  [not_found] path-dependencies (path dependencies of pubspec.yaml) - no flutter version found
+  [not_found] .metadata (.metadata) - no flutter version found
+Dart SDK: >=2.19.6 <3.0.0
+  [not_found] pubspec.lock (pubspec.lock) - no dart version found
+  [selected] pubspec.yaml (pubspec.yaml): >=2.19.6 <3.0.0 - highest precedence source with a version
+  [not_found] path-dependencies (path dependencies of pubspec.yaml) - no dart version found
+`, resolution.String())
+
+	b, err := json.Marshal(resolution)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+	"flutter_requirement": "version",
+	"flutter_constraint_mode": "as_written",
+	"flutter_version": "3.7.12",
+	"flutter_channel": "stable",
+	"dart_version_constraint": ">=2.19.6 <3.0.0",
+	"sources": [
+		{"source": "fvm", "file": ".fvmrc, .fvm/fvm_config.json, .fvm/version", "sdk": "flutter", "status": "not_found", "reason": "no flutter version found"},
+		{"source": "asdf", "file": ".tool-versions", "sdk": "flutter", "value": "3.7.12", "channel": "stable", "status": "selected", "reason": "highest precedence source with a version"},
+		{"source": "mise", "file": ".mise.local.toml, mise.local.toml, .mise.toml, .mise/config.toml, mise.toml, .config/mise.toml, .config/mise/config.toml, .rtx.toml", "sdk": "flutter", "status": "not_found", "reason": "no flutter version found"},
+		{"source": "puro", "file": ".puro.json", "sdk": "flutter", "status": "not_found", "reason": "no flutter version found"},
+		{"source": "proto", "file": ".prototools", "sdk": "flutter", "status": "not_found", "reason": "no flutter version found"},
+		{"source": "pubspec.lock", "file": "pubspec.lock", "sdk": "flutter", "status": "not_found", "reason": "no flutter version found"},
+		{"source": "pubspec.yaml", "file": "pubspec.yaml", "sdk": "flutter", "value": "^3.7.12", "status": "ignored", "reason": "overridden by asdf, which has higher precedence"},
+		{"source": "path-dependencies", "file": "path dependencies of pubspec.yaml", "sdk": "flutter", "status": "not_found", "reason": "no flutter version found"},
+		{"source": ".metadata", "file": ".metadata", "sdk": "flutter", "status": "not_found", "reason": "no flutter version found"},
+		{"source": "pubspec.lock", "file": "pubspec.lock", "sdk": "dart", "status": "not_found", "reason": "no dart version found"},
+		{"source": "pubspec.yaml", "file": "pubspec.yaml", "sdk": "dart", "value": ">=2.19.6 <3.0.0", "status": "selected", "reason": "highest precedence source with a version"},
+		{"source": "path-dependencies", "file": "path dependencies of pubspec.yaml", "sdk": "dart", "status": "not_found", "reason": "no dart version found"}
+	]
+}`, string(b))
+}
+
+func Test_createSDKQuery_ChannelHeadAndCommitPins(t *testing.T) {
+	tests := []struct {
+		name            string
+		modify          func(results []VersionSourceResult)
+		wantRequirement FlutterRequirementKind
+		wantChannel     string
+		wantCommit      string
+		wantSummary     string
+		wantSource      string
+	}{
+		{
+			name: "Channel head",
+			modify: func(results []VersionSourceResult) {
+				results[0].FlutterChannel = "beta"
+			},
+			wantRequirement: FlutterRequirementChannelHead,
+			wantChannel:     "beta",
+			wantSummary:     "Flutter SDK: head of the beta channel",
+			wantSource:      "  [selected] fvm (.fvmrc, .fvm/fvm_config.json, .fvm/version): beta - highest precedence source with a version",
+		},
+		{
+			name: "Commit",
+			modify: func(results []VersionSourceResult) {
+				results[1].FlutterCommit = "761747bfc538b5af34aa0d3fac380f1bc331ec49"
+			},
+			wantRequirement: FlutterRequirementCommit,
+			wantCommit:      "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+			wantSummary:     "Flutter SDK: commit 761747bfc538b5af34aa0d3fac380f1bc331ec49",
+			wantSource:      "  [selected] asdf (.tool-versions): 761747bfc538b5af34aa0d3fac380f1bc331ec49 - highest precedence source with a version",
+		},
+		{
+			name: "Metadata revision",
+			modify: func(results []VersionSourceResult) {
+				results[8].FlutterCommit = "761747bfc538b5af34aa0d3fac380f1bc331ec49"
+			},
+			wantRequirement: FlutterRequirementPreferredCommit,
+			wantCommit:      "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+			wantSummary:     "Flutter SDK: any (commit 761747bfc538b5af34aa0d3fac380f1bc331ec49 preferred)",
+			wantSource:      "  [selected] .metadata (.metadata): 761747bfc538b5af34aa0d3fac380f1bc331ec49 - preferred, because no other source requires a version",
+		},
+		{
+			name: "Metadata revision is ignored if another source pins Flutter",
+			modify: func(results []VersionSourceResult) {
+				results[0].FlutterChannel = "beta"
+				results[8].FlutterCommit = "761747bfc538b5af34aa0d3fac380f1bc331ec49"
+			},
+			wantRequirement: FlutterRequirementChannelHead,
+			wantChannel:     "beta",
+			wantSummary:     "Flutter SDK: head of the beta channel",
+			wantSource:      "  [ignored] .metadata (.metadata): 761747bfc538b5af34aa0d3fac380f1bc331ec49 - only preferred if no other source requires a flutter version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := emptyVersionSourceResults()
+			tt.modify(results)
+
+			resolution := createSDKQuery(results)
+			require.Equal(t, tt.wantRequirement, resolution.FlutterRequirement)
+			require.Equal(t, tt.wantChannel, resolution.FlutterChannel)
+			require.Equal(t, tt.wantCommit, resolution.FlutterCommit)
+			require.Nil(t, resolution.Query.FlutterVersion)
+			require.Nil(t, resolution.Query.FlutterVersionConstraint)
+
+			lines := strings.Split(resolution.String(), "\n")
+			require.Equal(t, tt.wantSummary, lines[0])
+			require.Contains(t, lines, tt.wantSource)
+		})
+	}
+}
+
+func Test_createSDKQuery_CombinedConstraints(t *testing.T) {
+	results := emptyVersionSourceResults()
+	results[5].DartVersionConstraint = mustParsePubConstraint(t, ">=3.1.0 <4.0.0")
+	results[6].FlutterVersionConstraint = mustParsePubConstraint(t, ">=3.10.0")
+	results[6].DartVersionConstraint = mustParsePubConstraint(t, "^3.0.0")
+	results[7].File = "packages/a/pubspec.yaml, packages/b/pubspec.yaml"
+	results[7].FlutterVersionConstraint = mustParsePubConstraint(t, ">=3.7.0")
+	results[7].DartVersionConstraint = mustParsePubConstraint(t, "<3.0.0")
+
+	resolution := createSDKQuery(results)
+
+	require.Equal(t, ">=3.10.0", resolution.FlutterVersionConstraint)
+	require.Equal(t, "<empty>", resolution.DartVersionConstraint)
+	require.Equal(t, []SDKRequirementConflictError{{
+		SDK:         "dart",
+		Source:      "path-dependencies",
+		File:        "packages/a/pubspec.yaml, packages/b/pubspec.yaml",
+		Value:       "<3.0.0",
+		Requirement: ">=3.1.0 <4.0.0",
+	}}, resolution.Conflicts)
+	require.Equal(t, `Flutter SDK: >=3.10.0
+  [not_found] fvm (.fvmrc, .fvm/fvm_config.json, .fvm/version) - no flutter version found
+  [not_found] asdf (.tool-versions) - no flutter version found
+  [not_found] mise (.mise.local.toml, mise.local.toml, .mise.toml, .mise/config.toml, mise.toml, .config/mise.toml, .config/mise/config.toml, .rtx.toml) - no flutter version found
+  [not_found] puro (.puro.json) - no flutter version found
+  [not_found] proto (.prototools) - no flutter version found
+  [not_found] pubspec.lock (pubspec.lock) - no flutter version found
+  [selected] pubspec.yaml (pubspec.yaml): >=3.10.0 - highest precedence source with a version
+  [combined] path-dependencies (packages/a/pubspec.yaml, packages/b/pubspec.yaml): >=3.7.0 - intersected with the requirement of pubspec.yaml
+  [not_found] .metadata (.metadata) - no flutter version found
+Dart SDK: <empty>
+  [selected] pubspec.lock (pubspec.lock): >=3.1.0 <4.0.0 - highest precedence source with a version
+  [combined] pubspec.yaml (pubspec.yaml): ^3.0.0 - intersected with the requirement of pubspec.lock
+  [conflicting] path-dependencies (packages/a/pubspec.yaml, packages/b/pubspec.yaml): <3.0.0 - doesn't allow any version of >=3.1.0 <4.0.0 required by the higher precedence sources
+`, resolution.String())
+}
+
+func mustParsePubConstraint(t *testing.T, constraint string) *sdk.Constraint {
+	c, err := sdk.ParseConstraint(constraint)
+	require.NoError(t, err)
+	return c
+}
+
+func Test_createSDKQuery_NoSources(t *testing.T) {
+	resolution := createSDKQuery(emptyVersionSourceResults())
+	require.Equal(t, `Flutter SDK: any (no requirement found)
+  [not_found] fvm (.fvmrc, .fvm/fvm_config.json, .fvm/version) - no flutter version found
+  [not_found] asdf (.tool-versions) - no flutter version found
+  [not_found] mise (.mise.local.toml, mise.local.toml, .mise.toml, .mise/config.toml, mise.toml, .config/mise.toml, .config/mise/config.toml, .rtx.toml) - no flutter version found
+  [not_found] puro (.puro.json) - no flutter version found
+  [not_found] proto (.prototools) - no flutter version found
+  [not_found] pubspec.lock (pubspec.lock) - no flutter version found
+  [not_found] pubspec.yaml (pubspec.yaml) - no flutter version found
+  [not_found] path-dependencies (path dependencies of pubspec.yaml) - no flutter version found
+  [not_found] .metadata (.metadata) - no flutter version found
+Dart SDK: any (no requirement found)
+  [not_found] pubspec.lock (pubspec.lock) - no dart version found
+  [not_found] pubspec.yaml (pubspec.yaml) - no dart version found
+  [not_found] path-dependencies (path dependencies of pubspec.yaml) - no dart version found
+`, resolution.String())
+}
+
+// emptyVersionSourceResults returns the results of the default version sources for a project without any SDK requirements.
+func emptyVersionSourceResults() []VersionSourceResult {
+	return []VersionSourceResult{
+		{Source: FVMVersionSourceName, File: ".fvmrc, .fvm/fvm_config.json, .fvm/version"},
+		{Source: ASDFVersionSourceName, File: ".tool-versions"},
+		{Source: MiseVersionSourceName, File: ".mise.local.toml, mise.local.toml, .mise.toml, .mise/config.toml, mise.toml, .config/mise.toml, .config/mise/config.toml, .rtx.toml"},
+		{Source: PuroVersionSourceName, File: ".puro.json"},
+		{Source: ProtoVersionSourceName, File: ".prototools"},
+		{Source: PubspecLockVersionSourceName, File: "pubspec.lock"},
+		{Source: PubspecVersionSourceName, File: "pubspec.yaml"},
+		{Source: PathDependencyVersionSourceName, File: "path dependencies of pubspec.yaml"},
+		{Source: MetadataVersionSourceName, File: ".metadata"},
+	}
+}
+
+func TestProject_ResolveSDKVersions_PubspecFlutterMinimumOnly(t *testing.T) {
+	tests := []struct {
+		name           string
+		pubspec        string
+		wantConstraint string
+		wantMode       FlutterConstraintMode
+		wantRaw        string
+		wantSource     string
+	}{
+		{
+			name:           "Range constraint",
+			pubspec:        "environment:\n  flutter: \">=3.7.0 <3.8.0\"",
+			wantConstraint: ">=3.7.0",
+			wantMode:       FlutterConstraintMinimumOnly,
+			wantRaw:        ">=3.7.0 <3.8.0",
+			wantSource:     "  [selected] pubspec.yaml (pubspec.yaml): >=3.7.0 <3.8.0 (applied as >=3.7.0) - highest precedence source with a version",
+		},
+		{
+			name:           "Exact version",
+			pubspec:        "environment:\n  flutter: 3.7.12",
+			wantConstraint: ">=3.7.12",
+			wantMode:       FlutterConstraintMinimumOnly,
+			wantRaw:        "3.7.12",
+			wantSource:     "  [selected] pubspec.yaml (pubspec.yaml): 3.7.12 (applied as >=3.7.12) - highest precedence source with a version",
+		},
+		{
+			name:           "Minimum only constraint",
+			pubspec:        "environment:\n  flutter: \">=3.7.0\"",
+			wantConstraint: ">=3.7.0",
+			wantMode:       FlutterConstraintAsWritten,
+			wantSource:     "  [selected] pubspec.yaml (pubspec.yaml): >=3.7.0 - highest precedence source with a version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileManager := new(mocks.FileManager)
+			fileManager.On("OpenReaderIfExists", "pubspec.yaml").Return(func(string) io.Reader { return strings.NewReader(tt.pubspec) }, nil)
+			fileManager.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			p := &Project{fileManager: fileManager}
+			resolution, err := p.ResolveSDKVersions()
+			require.NoError(t, err)
+
+			require.Equal(t, FlutterRequirementConstraint, resolution.FlutterRequirement)
+			require.Equal(t, tt.wantConstraint, resolution.FlutterVersionConstraint)
+			require.Empty(t, resolution.FlutterVersion)
+			require.Equal(t, tt.wantMode, resolution.FlutterConstraintMode)
+			require.Equal(t, tt.wantRaw, resolution.RawFlutterRequirement)
+			require.True(t, resolution.Query.FlutterVersionConstraint.Check(semver.MustParse("3.10.0")))
+
+			lines := strings.Split(resolution.String(), "\n")
+			require.Contains(t, lines, tt.wantSource)
+		})
+	}
+}
@@ -0,0 +1,494 @@
+package flutterproject
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/bitrise-io/go-flutter/flutterproject/internal/sdk"
+	"github.com/bitrise-io/go-flutter/fluttersdk"
+)
+
+// Names of the built-in SDK version sources.
+const (
+	FVMVersionSourceName            = "fvm"
+	ASDFVersionSourceName           = "asdf"
+	MiseVersionSourceName           = "mise"
+	PuroVersionSourceName           = "puro"
+	ProtoVersionSourceName          = "proto"
+	PubspecLockVersionSourceName    = "pubspec.lock"
+	PubspecVersionSourceName        = "pubspec.yaml"
+	PathDependencyVersionSourceName = "path-dependencies"
+	MetadataVersionSourceName       = ".metadata"
+
+	GitHubActionsVersionSourceName = "github-actions"
+	CodemagicVersionSourceName     = "codemagic"
+	BitriseVersionSourceName       = "bitrise"
+)
+
+type FileOpener interface {
+	OpenReaderIfExists(path string) (io.Reader, error)
+}
+
+type DirEntryReader interface {
+	ReadDirEntryNames(path string) ([]string, error)
+}
+
+// VersionSourceContext is passed to the SDK version sources when reading the project's SDK requirements.
+type VersionSourceContext struct {
+	RootDir    string
+	FileOpener FileOpener
+	DirReader  DirEntryReader
+}
+
+/*
+VersionSourceResult is the SDK requirement provided by a version source.
+
+Either an exact version or a constraint is set per SDK, both of them are empty if the source doesn't define a requirement.
+The Flutter SDK can also be pinned to a framework commit (FlutterCommit),
+or to the head of a channel (only FlutterChannel is set).
+File is the project relative path of the file the requirement was read from,
+or the list of the looked up files if the requirement was not found.
+
+The Flutter requirement is the one written in the file. EffectiveFlutterVersionConstraint is set if the Flutter tool enforces
+a different constraint, like the lower bound only of pubspec.yaml's environment.flutter, the resolution applies this one.
+*/
+type VersionSourceResult struct {
+	Source                   string
+	File                     string
+	FlutterVersion           *semver.Version
+	FlutterVersionConstraint fluttersdk.VersionConstraint
+	FlutterChannel           string
+	FlutterCommit            string
+	DartVersion              *semver.Version
+	DartVersionConstraint    fluttersdk.VersionConstraint
+
+	EffectiveFlutterVersionConstraint fluttersdk.VersionConstraint
+}
+
+// VersionSource reads Flutter and Dart SDK requirements from a project, for example from a version manager's config file.
+// Read returns a nil result if the project doesn't use the source.
+type VersionSource interface {
+	Name() string
+	Read(c VersionSourceContext) (*VersionSourceResult, error)
+}
+
+// VersionSourceRegistry is the ordered list of SDK version sources, earlier sources have higher precedence.
+type VersionSourceRegistry struct {
+	sources []VersionSource
+}
+
+func NewVersionSourceRegistry(sources ...VersionSource) *VersionSourceRegistry {
+	return &VersionSourceRegistry{sources: sources}
+}
+
+// DefaultVersionSources returns the built-in SDK version sources in their default precedence order.
+// The .metadata source's commit is only preferred if none of the other sources requires a Flutter version.
+func DefaultVersionSources() []VersionSource {
+	return []VersionSource{
+		fvmVersionSource{},
+		asdfVersionSource{},
+		miseVersionSource{},
+		puroVersionSource{},
+		protoVersionSource{},
+		pubspecLockVersionSource{},
+		pubspecVersionSource{},
+		pathDependencyVersionSource{},
+		metadataVersionSource{},
+	}
+}
+
+/*
+CIVersionSources returns the opt-in SDK version sources, which read the Flutter version set up by the project's CI configs:
+- the subosito/flutter-action step of GitHub workflows (.github/workflows/*.yml)
+- the workflow environment of codemagic.yaml
+- the flutter-installer step of bitrise.yml
+
+These sources are not registered by default, add them to a project's registry with:
+
+	err := p.VersionSources().Add(flutterproject.CIVersionSources()...)
+*/
+func CIVersionSources() []VersionSource {
+	return []VersionSource{
+		githubActionsVersionSource{},
+		codemagicVersionSource{},
+		bitriseVersionSource{},
+	}
+}
+
+func (r *VersionSourceRegistry) Sources() []VersionSource {
+	return append([]VersionSource{}, r.sources...)
+}
+
+// Add registers the sources with the lowest precedence, source names must be unique.
+func (r *VersionSourceRegistry) Add(sources ...VersionSource) error {
+	var names []string
+	for _, source := range sources {
+		if r.indexOf(source.Name()) != -1 || containsString(names, source.Name()) {
+			return fmt.Errorf("version source already registered: %s", source.Name())
+		}
+		names = append(names, source.Name())
+	}
+
+	r.sources = append(r.sources, sources...)
+	return nil
+}
+
+// AddBefore registers the source with higher precedence than the named source, source names must be unique.
+func (r *VersionSourceRegistry) AddBefore(name string, source VersionSource) error {
+	idx := r.indexOf(name)
+	if idx == -1 {
+		return fmt.Errorf("version source not found: %s", name)
+	}
+	if r.indexOf(source.Name()) != -1 {
+		return fmt.Errorf("version source already registered: %s", source.Name())
+	}
+
+	r.sources = append(r.sources[:idx], append([]VersionSource{source}, r.sources[idx:]...)...)
+	return nil
+}
+
+// Remove disables the named source.
+func (r *VersionSourceRegistry) Remove(name string) error {
+	idx := r.indexOf(name)
+	if idx == -1 {
+		return fmt.Errorf("version source not found: %s", name)
+	}
+
+	r.sources = append(r.sources[:idx], r.sources[idx+1:]...)
+	return nil
+}
+
+// Reorder moves the named sources to the front in the given order, the rest of the sources keep their relative order.
+func (r *VersionSourceRegistry) Reorder(names ...string) error {
+	var reordered []VersionSource
+	for i, name := range names {
+		if containsString(names[:i], name) {
+			return fmt.Errorf("version source listed more than once: %s", name)
+		}
+
+		idx := r.indexOf(name)
+		if idx == -1 {
+			return fmt.Errorf("version source not found: %s", name)
+		}
+		reordered = append(reordered, r.sources[idx])
+	}
+
+	for _, source := range r.sources {
+		if !containsString(names, source.Name()) {
+			reordered = append(reordered, source)
+		}
+	}
+
+	r.sources = reordered
+	return nil
+}
+
+func (r *VersionSourceRegistry) indexOf(name string) int {
+	for i, source := range r.sources {
+		if source.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Read reads every registered source in precedence order, every source has a result, even if it doesn't define a requirement.
+func (r *VersionSourceRegistry) Read(c VersionSourceContext) ([]VersionSourceResult, error) {
+	var results []VersionSourceResult
+	for _, source := range r.sources {
+		result, err := source.Read(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s SDK versions: %w", source.Name(), err)
+		}
+		if result == nil {
+			result = &VersionSourceResult{}
+		}
+		result.Source = source.Name()
+
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// fvmVersionSource reads the SDK pinned for the flavor, or the project's SDK if flavor is empty.
+type fvmVersionSource struct {
+	flavor string
+}
+
+func (fvmVersionSource) Name() string {
+	return FVMVersionSourceName
+}
+
+func (s fvmVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	pin, configFile, err := sdk.NewFVMVersionReader(c.FileOpener).ReadSDKVersion(c.RootDir, s.flavor)
+	if err != nil {
+		return nil, err
+	}
+	if configFile == "" {
+		configFile = strings.Join(sdk.FVMConfigRelPaths, ", ")
+	}
+
+	return newFlutterPinResult(configFile, pin), nil
+}
+
+type asdfVersionSource struct{}
+
+func (asdfVersionSource) Name() string {
+	return ASDFVersionSourceName
+}
+
+func (asdfVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	pin, configFile, err := sdk.NewASDFVersionReader(c.FileOpener).ReadSDKVersions(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	if configFile == "" {
+		configFile = sdk.ASDFConfigRelPath
+	}
+
+	return newFlutterPinResult(configFile, pin), nil
+}
+
+type miseVersionSource struct{}
+
+func (miseVersionSource) Name() string {
+	return MiseVersionSourceName
+}
+
+func (miseVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	version, channel, configFile, err := sdk.NewMiseVersionReader(c.FileOpener).ReadSDKVersion(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	if configFile == "" {
+		configFile = strings.Join(sdk.MiseConfigRelPaths, ", ")
+	}
+
+	result := newVersionConstraintResult(configFile, version, nil)
+	result.FlutterChannel = channel
+	return result, nil
+}
+
+type puroVersionSource struct{}
+
+func (puroVersionSource) Name() string {
+	return PuroVersionSourceName
+}
+
+func (puroVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	version, channel, err := sdk.NewPuroVersionReader(c.FileOpener).ReadSDKVersion(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VersionSourceResult{File: sdk.PuroConfigRelPath, FlutterVersion: version, FlutterChannel: channel}, nil
+}
+
+type protoVersionSource struct{}
+
+func (protoVersionSource) Name() string {
+	return ProtoVersionSourceName
+}
+
+func (protoVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	version, channel, err := sdk.NewProtoVersionReader(c.FileOpener).ReadSDKVersion(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newVersionConstraintResult(sdk.ProtoConfigRelPath, version, nil)
+	result.FlutterChannel = channel
+	return result, nil
+}
+
+type pubspecLockVersionSource struct{}
+
+func (pubspecLockVersionSource) Name() string {
+	return PubspecLockVersionSourceName
+}
+
+func (pubspecLockVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	flutterVersion, dartVersion, err := sdk.NewPubspecLockVersionReader(c.FileOpener).ReadSDKVersions(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return newVersionConstraintResult(sdk.PubspecLockRelPath, flutterVersion, dartVersion), nil
+}
+
+type pubspecVersionSource struct{}
+
+func (pubspecVersionSource) Name() string {
+	return PubspecVersionSourceName
+}
+
+func (pubspecVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	flutterVersion, dartVersion, err := sdk.NewPubspecVersionReader(c.FileOpener).ReadSDKVersions(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newVersionConstraintResult(sdk.PubspecRelPath, flutterVersion, dartVersion)
+	if flutterVersion != nil {
+		// Flutter ignores the upper bound of environment.flutter
+		effective := flutterVersion.MinimumOnly()
+		if flutterVersion.Constraint == nil || !flutterVersion.Constraint.Equal(effective.Constraint) {
+			result.EffectiveFlutterVersionConstraint = effective.Constraint
+		}
+	}
+	return result, nil
+}
+
+type pathDependencyVersionSource struct{}
+
+func (pathDependencyVersionSource) Name() string {
+	return PathDependencyVersionSourceName
+}
+
+// Read intersects the SDK constraints of the project's path dependencies, Flutter constraints are applied as minimum only.
+func (pathDependencyVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	dependencies, err := sdk.NewPathDependencyReader(c.FileOpener).ReadPathDependencies(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	var flutterVersion, dartVersion *sdk.Constraint
+	for _, dependency := range dependencies {
+		if dependency.FlutterVersion == nil && dependency.DartVersion == nil {
+			continue
+		}
+		files = append(files, dependency.PubspecPath)
+		if dependency.FlutterVersion != nil {
+			flutterVersion = intersectConstraints(flutterVersion, dependency.FlutterVersion.MinimumOnly().Constraint)
+		}
+		if dependency.DartVersion != nil {
+			dartVersion = intersectConstraints(dartVersion, toPubConstraint(dependency.DartVersion.Version, dependency.DartVersion.Constraint))
+		}
+	}
+
+	result := &VersionSourceResult{File: strings.Join(files, ", ")}
+	if len(files) == 0 {
+		result.File = "path dependencies of " + sdk.PubspecRelPath
+	}
+	if flutterVersion != nil {
+		result.FlutterVersionConstraint = flutterVersion
+	}
+	if dartVersion != nil {
+		result.DartVersionConstraint = dartVersion
+	}
+	return result, nil
+}
+
+// metadataVersionSource reads the framework revision the project was created or last migrated with.
+type metadataVersionSource struct{}
+
+func (metadataVersionSource) Name() string {
+	return MetadataVersionSourceName
+}
+
+func (metadataVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	revision, _, err := sdk.NewMetadataReader(c.FileOpener).ReadRevision(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VersionSourceResult{File: sdk.MetadataRelPath, FlutterCommit: revision}, nil
+}
+
+type githubActionsVersionSource struct{}
+
+func (githubActionsVersionSource) Name() string {
+	return GitHubActionsVersionSourceName
+}
+
+func (githubActionsVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	if c.DirReader == nil {
+		return nil, fmt.Errorf("no DirReader set in the version source context, %s can't be listed", sdk.GitHubWorkflowsRelDir)
+	}
+
+	version, channel, workflowFile, err := sdk.NewGitHubActionsVersionReader(c.FileOpener, c.DirReader).ReadSDKVersion(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	if workflowFile == "" {
+		workflowFile = sdk.GitHubWorkflowsRelDir
+	}
+
+	result := newVersionConstraintResult(workflowFile, version, nil)
+	result.FlutterChannel = channel
+	return result, nil
+}
+
+type codemagicVersionSource struct{}
+
+func (codemagicVersionSource) Name() string {
+	return CodemagicVersionSourceName
+}
+
+func (codemagicVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	version, channel, err := sdk.NewCodemagicVersionReader(c.FileOpener).ReadSDKVersion(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newVersionConstraintResult(sdk.CodemagicConfigRelPath, version, nil)
+	result.FlutterChannel = channel
+	return result, nil
+}
+
+type bitriseVersionSource struct{}
+
+func (bitriseVersionSource) Name() string {
+	return BitriseVersionSourceName
+}
+
+func (bitriseVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	version, channel, err := sdk.NewBitriseVersionReader(c.FileOpener).ReadSDKVersion(c.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newVersionConstraintResult(sdk.BitriseConfigRelPath, version, nil)
+	result.FlutterChannel = channel
+	return result, nil
+}
+
+func newFlutterPinResult(file string, pin *sdk.FlutterPin) *VersionSourceResult {
+	result := VersionSourceResult{File: file}
+	if pin != nil {
+		result.FlutterVersion = pin.Version
+		result.FlutterChannel = pin.Channel
+		result.FlutterCommit = pin.Commit
+	}
+	return &result
+}
+
+func newVersionConstraintResult(file string, flutterVersion, dartVersion *sdk.VersionConstraint) *VersionSourceResult {
+	result := VersionSourceResult{File: file}
+	// a nil *sdk.Constraint would be a non-nil fluttersdk.VersionConstraint
+	if flutterVersion != nil {
+		result.FlutterVersion = flutterVersion.Version
+		if flutterVersion.Constraint != nil {
+			result.FlutterVersionConstraint = flutterVersion.Constraint
+		}
+	}
+	if dartVersion != nil {
+		result.DartVersion = dartVersion.Version
+		if dartVersion.Constraint != nil {
+			result.DartVersionConstraint = dartVersion.Constraint
+		}
+	}
+	return &result
+}
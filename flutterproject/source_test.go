@@ -0,0 +1,192 @@
+package flutterproject
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/bitrise-io/go-flutter/fluttersdk"
+	"github.com/bitrise-io/go-flutter/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type toolchainVersionSource struct {
+	version string
+}
+
+func (s toolchainVersionSource) Name() string {
+	return "toolchain.yaml"
+}
+
+func (s toolchainVersionSource) Read(c VersionSourceContext) (*VersionSourceResult, error) {
+	return &VersionSourceResult{File: "toolchain.yaml", FlutterVersion: semver.MustParse(s.version)}, nil
+}
+
+func TestVersionSourceRegistry(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(r *VersionSourceRegistry) error
+		wantNames []string
+		wantErr   string
+	}{
+		{
+			name:      "Default sources",
+			modify:    func(r *VersionSourceRegistry) error { return nil },
+			wantNames: []string{"fvm", "asdf", "mise", "puro", "proto", "pubspec.lock", "pubspec.yaml", "path-dependencies", ".metadata"},
+		},
+		{
+			name: "Add custom source with highest precedence",
+			modify: func(r *VersionSourceRegistry) error {
+				return r.AddBefore(FVMVersionSourceName, toolchainVersionSource{})
+			},
+			wantNames: []string{"toolchain.yaml", "fvm", "asdf", "mise", "puro", "proto", "pubspec.lock", "pubspec.yaml", "path-dependencies", ".metadata"},
+		},
+		{
+			name: "Add custom source with lowest precedence",
+			modify: func(r *VersionSourceRegistry) error {
+				return r.Add(toolchainVersionSource{})
+			},
+			wantNames: []string{"fvm", "asdf", "mise", "puro", "proto", "pubspec.lock", "pubspec.yaml", "path-dependencies", ".metadata", "toolchain.yaml"},
+		},
+		{
+			name: "Remove and reorder built-in sources",
+			modify: func(r *VersionSourceRegistry) error {
+				if err := r.Remove(ProtoVersionSourceName); err != nil {
+					return err
+				}
+				return r.Reorder(ASDFVersionSourceName, MiseVersionSourceName)
+			},
+			wantNames: []string{"asdf", "mise", "fvm", "puro", "pubspec.lock", "pubspec.yaml", "path-dependencies", ".metadata"},
+		},
+		{
+			name: "Unknown source",
+			modify: func(r *VersionSourceRegistry) error {
+				return r.Remove("unknown")
+			},
+			wantErr: "version source not found: unknown",
+		},
+		{
+			name: "Add registered source",
+			modify: func(r *VersionSourceRegistry) error {
+				return r.Add(toolchainVersionSource{}, toolchainVersionSource{})
+			},
+			wantErr: "version source already registered: toolchain.yaml",
+		},
+		{
+			name: "Add registered source before another source",
+			modify: func(r *VersionSourceRegistry) error {
+				return r.AddBefore(ASDFVersionSourceName, fvmVersionSource{})
+			},
+			wantErr: "version source already registered: fvm",
+		},
+		{
+			name: "Reorder the same source twice",
+			modify: func(r *VersionSourceRegistry) error {
+				return r.Reorder(FVMVersionSourceName, FVMVersionSourceName)
+			},
+			wantErr: "version source listed more than once: fvm",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewVersionSourceRegistry(DefaultVersionSources()...)
+			err := tt.modify(registry)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var gotNames []string
+			for _, source := range registry.Sources() {
+				gotNames = append(gotNames, source.Name())
+			}
+			require.Equal(t, tt.wantNames, gotNames)
+		})
+	}
+}
+
+func TestProject_VersionSources_CustomSource(t *testing.T) {
+	availableSDKLister := new(mocks.SDKVersionLister)
+	availableSDKLister.On("ListReleasesByChannel", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]fluttersdk.Release{"stable": {
+		{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5"},
+		{Channel: "stable", Version: "3.13.8", DartSdkVersion: "3.1.4"},
+	}}, nil)
+
+	fileOpener := new(mocks.FileManager)
+	fileOpener.On("OpenReaderIfExists", ".tool-versions").Return(strings.NewReader("flutter 3.13.9"), nil)
+	fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+	p := &Project{
+		fileManager:      fileOpener,
+		sdkVersionFinder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister},
+	}
+	require.NoError(t, p.VersionSources().AddBefore(FVMVersionSourceName, toolchainVersionSource{version: "3.13.8"}))
+
+	resolution, err := p.ResolveSDKVersions()
+	require.NoError(t, err)
+	require.Equal(t, "3.13.8", resolution.FlutterVersion)
+	require.Equal(t, "toolchain.yaml", resolution.Sources[0].Source)
+	require.Equal(t, SDKVersionSourceSelected, resolution.Sources[0].Status)
+
+	release, err := p.FlutterSDKReleaseToUse(fluttersdk.MacOS, fluttersdk.ARM64)
+	require.NoError(t, err)
+	require.Equal(t, "3.13.8", release.Version)
+}
+
+func TestProject_FlutterAndDartSDKVersions_CIVersionSources(t *testing.T) {
+	workflow := `jobs:
+  test:
+    steps:
+      - uses: subosito/flutter-action@v2
+        with:
+          flutter-version: 3.22.x
+          channel: stable
+`
+	codemagicConfig := `workflows:
+  ios-workflow:
+    environment:
+      flutter: beta
+`
+	bitriseConfig := `workflows:
+  primary:
+    steps:
+    - flutter-installer@0:
+        inputs:
+        - version: 3.22.2-stable
+`
+
+	fileManager := new(mocks.FileManager)
+	fileManager.On("ReadDirEntryNames", ".github/workflows").Return([]string{"release.yml", "README.md", "ci.yml"}, nil)
+	fileManager.On("OpenReaderIfExists", ".github/workflows/ci.yml").Return(func(string) io.Reader { return strings.NewReader(workflow) }, nil)
+	fileManager.On("OpenReaderIfExists", "codemagic.yaml").Return(func(string) io.Reader { return strings.NewReader(codemagicConfig) }, nil)
+	fileManager.On("OpenReaderIfExists", "bitrise.yml").Return(func(string) io.Reader { return strings.NewReader(bitriseConfig) }, nil)
+	fileManager.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+	p := &Project{fileManager: fileManager}
+	require.NoError(t, p.VersionSources().Add(CIVersionSources()...))
+
+	sdkVersions, err := p.FlutterAndDartSDKVersions()
+	require.NoError(t, err)
+
+	require.Equal(t, "3.22.x", sdkVersions.GitHubActionsFlutterVersion.Constraint.String())
+	require.Equal(t, "stable", sdkVersions.GitHubActionsFlutterChannel)
+	require.Equal(t, ".github/workflows/ci.yml", sdkVersions.GitHubActionsWorkflowFile)
+	require.Nil(t, sdkVersions.CodemagicFlutterVersion)
+	require.Equal(t, "beta", sdkVersions.CodemagicFlutterChannel)
+	require.Equal(t, "3.22.2", sdkVersions.BitriseFlutterVersion.Version.String())
+	require.Equal(t, "stable", sdkVersions.BitriseFlutterChannel)
+
+	resolution, err := p.ResolveSDKVersions()
+	require.NoError(t, err)
+	require.Equal(t, "3.22.x", resolution.FlutterVersionConstraint)
+	require.Equal(t, "stable", resolution.FlutterChannel)
+}
+
+func TestGitHubActionsVersionSource_WithoutDirReader(t *testing.T) {
+	registry := NewVersionSourceRegistry(CIVersionSources()...)
+	_, err := registry.Read(VersionSourceContext{FileOpener: new(mocks.FileManager)})
+	require.EqualError(t, err, "failed to read github-actions SDK versions: no DirReader set in the version source context, .github/workflows can't be listed")
+}
@@ -0,0 +1,160 @@
+package flutterproject
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/bitrise-io/go-flutter/fluttersdk"
+)
+
+type SDKVersionConflictKind string
+
+const (
+	// PinMismatch means two sources pin different Flutter versions or channels.
+	PinMismatch SDKVersionConflictKind = "pin_mismatch"
+	// PinOutsideConstraint means a pinned version doesn't satisfy another source's constraint.
+	PinOutsideConstraint SDKVersionConflictKind = "pin_outside_constraint"
+	// BundledDartOutsideConstraint means the Dart SDK bundled with the pinned Flutter release doesn't satisfy a Dart constraint.
+	BundledDartOutsideConstraint SDKVersionConflictKind = "bundled_dart_outside_constraint"
+	// PinnedReleaseNotFound means the pinned Flutter version is not a known release, so its bundled Dart SDK can't be checked.
+	PinnedReleaseNotFound SDKVersionConflictKind = "pinned_release_not_found"
+)
+
+// SDKVersionConflict describes a disagreement between the project's SDK version sources.
+type SDKVersionConflict struct {
+	Kind    SDKVersionConflictKind `json:"kind"`
+	SDK     string                 `json:"sdk"`
+	Source  string                 `json:"source"`
+	File    string                 `json:"file"`
+	Value   string                 `json:"value"`
+	Other   *SDKVersionSourceRef   `json:"other,omitempty"`
+	Message string                 `json:"message"`
+}
+
+// SDKVersionSourceRef points to the other party of a conflict.
+type SDKVersionSourceRef struct {
+	Source string `json:"source"`
+	File   string `json:"file"`
+	SDK    string `json:"sdk"`
+	Value  string `json:"value"`
+}
+
+func (c SDKVersionConflict) String() string {
+	return c.Message
+}
+
+/*
+ValidateSDKVersions cross-checks the project's SDK version sources and returns the conflicts between them:
+- every pinned Flutter version (like FVM or asdf) against the other pins and every Flutter constraint
+- the Dart SDK bundled with every pinned Flutter release against every Dart constraint
+
+The bundled Dart SDK version is looked up in the releases of the given platform and architecture.
+*/
+func (p *Project) ValidateSDKVersions(platform fluttersdk.Platform, architecture fluttersdk.Architecture) ([]SDKVersionConflict, error) {
+	results, err := p.readVersionSources()
+	if err != nil {
+		return nil, err
+	}
+
+	flutterCandidates := flutterVersionCandidates(results)
+	dartCandidates := dartVersionCandidates(results)
+
+	var conflicts []SDKVersionConflict
+	var pins []sdkVersionCandidate
+	for _, candidate := range flutterCandidates {
+		if candidate.version == nil {
+			continue
+		}
+
+		for _, pin := range pins {
+			if !pin.version.Equal(candidate.version) || (pin.channel != "" && candidate.channel != "" && pin.channel != candidate.channel) {
+				conflicts = append(conflicts, newSDKVersionConflict(PinMismatch, flutterSDK, candidate, flutterSDK, pin,
+					fmt.Sprintf("%s pins Flutter %s, but %s pins Flutter %s", candidate.source, pinValue(candidate), pin.source, pinValue(pin))))
+			}
+		}
+		pins = append(pins, candidate)
+
+		for _, other := range flutterCandidates {
+			if other.constraint == nil || other.constraint.Check(candidate.version) {
+				continue
+			}
+			conflicts = append(conflicts, newSDKVersionConflict(PinOutsideConstraint, flutterSDK, candidate, flutterSDK, other,
+				fmt.Sprintf("%s pins Flutter %s, which doesn't satisfy the %s constraint %s", candidate.source, candidate.value(), other.source, other.writtenValue())))
+		}
+	}
+
+	for _, pin := range pins {
+		dartVersion, err := p.bundledDartVersion(platform, architecture, pin)
+		if err != nil {
+			return nil, err
+		}
+		if dartVersion == nil {
+			conflicts = append(conflicts, SDKVersionConflict{
+				Kind:    PinnedReleaseNotFound,
+				SDK:     flutterSDK,
+				Source:  pin.source,
+				File:    pin.file,
+				Value:   pinValue(pin),
+				Message: fmt.Sprintf("%s pins Flutter %s, which is not an available %s %s release", pin.source, pinValue(pin), platform, architecture),
+			})
+			continue
+		}
+
+		for _, other := range dartCandidates {
+			if other.found() && !dartCandidateAllows(other, dartVersion) {
+				conflicts = append(conflicts, newSDKVersionConflict(BundledDartOutsideConstraint, flutterSDK, pin, dartSDK, other,
+					fmt.Sprintf("%s pins Flutter %s, which bundles Dart %s, but %s requires Dart %s", pin.source, pin.value(), dartVersion, other.source, other.value())))
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+func (p *Project) bundledDartVersion(platform fluttersdk.Platform, architecture fluttersdk.Architecture, pin sdkVersionCandidate) (*semver.Version, error) {
+	release, err := p.sdkVersionFinder.FindLatestReleaseFor(platform, architecture, fluttersdk.Channel(pin.channel), fluttersdk.SDKQuery{FlutterVersion: pin.version})
+	if err != nil {
+		var noMatchingReleaseErr fluttersdk.NoMatchingReleaseError
+		if errors.As(err, &noMatchingReleaseErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if release == nil {
+		return nil, nil
+	}
+
+	return fluttersdk.ParseDartSDKVersion(release.DartSdkVersion)
+}
+
+func dartCandidateAllows(candidate sdkVersionCandidate, version *semver.Version) bool {
+	if candidate.version != nil {
+		return candidate.version.Equal(version)
+	}
+	return candidate.constraint.Check(version)
+}
+
+func pinValue(candidate sdkVersionCandidate) string {
+	if channel := candidate.qualifiedChannel(); channel != "" {
+		return candidate.writtenValue() + "@" + channel
+	}
+	return candidate.writtenValue()
+}
+
+func newSDKVersionConflict(kind SDKVersionConflictKind, sdkName string, candidate sdkVersionCandidate, otherSDKName string, other sdkVersionCandidate, message string) SDKVersionConflict {
+	return SDKVersionConflict{
+		Kind:   kind,
+		SDK:    sdkName,
+		Source: candidate.source,
+		File:   candidate.file,
+		Value:  pinValue(candidate),
+		Other: &SDKVersionSourceRef{
+			Source: other.source,
+			File:   other.file,
+			SDK:    otherSDKName,
+			Value:  pinValue(other),
+		},
+		Message: message,
+	}
+}
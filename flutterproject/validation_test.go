@@ -0,0 +1,103 @@
+package flutterproject
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-flutter/fluttersdk"
+	"github.com/bitrise-io/go-flutter/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProject_ValidateSDKVersions(t *testing.T) {
+	tests := []struct {
+		name          string
+		fvmConfig     string
+		toolVersions  string
+		pubspec       string
+		wantConflicts []string
+	}{
+		{
+			name:         "Sources agree",
+			fvmConfig:    `{"flutterSdkVersion": "3.10.6"}`,
+			toolVersions: "flutter 3.10.6-stable",
+			pubspec: `environment:
+  sdk: ">=3.0.0 <4.0.0"
+  flutter: ^3.10.0`,
+		},
+		{
+			name:      "Pinned version outside of the pubspec constraints",
+			fvmConfig: `{"flutterSdkVersion": "3.7.12"}`,
+			pubspec: `environment:
+  sdk: ">=3.0.0 <4.0.0"
+  flutter: ^3.10.0`,
+			wantConflicts: []string{
+				"fvm pins Flutter 3.7.12, which doesn't satisfy the pubspec.yaml constraint ^3.10.0",
+				"fvm pins Flutter 3.7.12, which bundles Dart 2.19.6, but pubspec.yaml requires Dart >=3.0.0 <4.0.0",
+			},
+		},
+		{
+			name:         "Pins disagree",
+			fvmConfig:    `{"flutterSdkVersion": "3.7.12@stable"}`,
+			toolVersions: "flutter 3.13.0-stable",
+			wantConflicts: []string{
+				"asdf pins Flutter 3.13.0@stable, but fvm pins Flutter 3.7.12@stable",
+				"asdf pins Flutter 3.13.0@stable, which is not an available macos arm64 release",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availableSDKLister := new(mocks.SDKVersionLister)
+			availableSDKLister.On("ListReleasesByChannel", mock.Anything, fluttersdk.MacOS, fluttersdk.ARM64).Return(map[string][]fluttersdk.Release{"stable": {
+				{Channel: "stable", Version: "3.10.6", DartSdkVersion: "3.0.6"},
+				{Channel: "stable", Version: "3.7.12", DartSdkVersion: "2.19.6"},
+			}}, nil)
+
+			fileOpener := new(mocks.FileManager)
+			fileOpener.On("OpenReaderIfExists", ".fvm/fvm_config.json").Return(optionalReader(tt.fvmConfig), nil)
+			fileOpener.On("OpenReaderIfExists", ".tool-versions").Return(optionalReader(tt.toolVersions), nil)
+			fileOpener.On("OpenReaderIfExists", "pubspec.lock").Return(nil, nil)
+			fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(optionalReader(tt.pubspec), nil)
+			fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+			p := &Project{
+				fileManager:      fileOpener,
+				sdkVersionFinder: fluttersdk.SDKVersionFinder{SDKVersionLister: availableSDKLister},
+			}
+			conflicts, err := p.ValidateSDKVersions(fluttersdk.MacOS, fluttersdk.ARM64)
+			require.NoError(t, err)
+
+			var gotConflicts []string
+			for _, conflict := range conflicts {
+				gotConflicts = append(gotConflicts, conflict.String())
+			}
+			require.Equal(t, tt.wantConflicts, gotConflicts)
+		})
+	}
+}
+
+func TestProject_ValidateSDKVersions_NilRelease(t *testing.T) {
+	fileOpener := new(mocks.FileManager)
+	fileOpener.On("OpenReaderIfExists", ".fvm/fvm_config.json").Return(strings.NewReader(`{"flutterSdkVersion": "3.10.6"}`), nil)
+	fileOpener.On("OpenReaderIfExists", "pubspec.yaml").Return(strings.NewReader("environment:\n  sdk: \">=3.0.0 <4.0.0\""), nil)
+	fileOpener.On("OpenReaderIfExists", mock.Anything).Return(nil, nil)
+
+	p := &Project{fileManager: fileOpener, sdkVersionFinder: nilReleaseFinder{}}
+	conflicts, err := p.ValidateSDKVersions(fluttersdk.MacOS, fluttersdk.ARM64)
+	require.NoError(t, err)
+
+	var gotConflicts []string
+	for _, conflict := range conflicts {
+		gotConflicts = append(gotConflicts, conflict.String())
+	}
+	require.Equal(t, []string{"fvm pins Flutter 3.10.6, which is not an available macos arm64 release"}, gotConflicts)
+}
+
+func optionalReader(content string) interface{} {
+	if content == "" {
+		return nil
+	}
+	return strings.NewReader(content)
+}
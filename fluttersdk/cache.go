@@ -0,0 +1,295 @@
+package fluttersdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CacheStatus tells where the releases manifest was served from.
+type CacheStatus string
+
+const (
+	// CacheStatusFresh means the manifest was downloaded, because it wasn't cached or the cached copy was outdated.
+	CacheStatusFresh CacheStatus = "fresh"
+	// CacheStatusRevalidated means the server confirmed that the cached manifest is up-to-date.
+	CacheStatusRevalidated CacheStatus = "revalidated"
+	// CacheStatusStale means the manifest couldn't be downloaded and an earlier cached copy was served.
+	CacheStatusStale CacheStatus = "stale"
+)
+
+/*
+CachingSDKVersionLister is an SDKVersionLister decorator, which stores the listed releases in a cache directory.
+
+Listers created by NewSDKVersionLister are cached by their releases manifests: every listing revalidates the cached manifest
+with the If-None-Match and If-Modified-Since headers, so the manifest is only downloaded again if it has changed.
+Other listers are called on every listing and their releases are cached as they were listed.
+If the releases can't be listed (for example when offline), the cached copy is served.
+*/
+type CachingSDKVersionLister struct {
+	lister   SDKVersionLister
+	cacheDir string
+
+	mu         sync.Mutex
+	lastStatus CacheStatus
+}
+
+// releasesManifestFetcher is implemented by listers, which support conditional releases manifest requests.
+type releasesManifestFetcher interface {
+	fetchReleasesManifest(ctx context.Context, platform Platform, metadata manifestMetadata) (*releasesManifest, error)
+}
+
+func NewCachingSDKVersionLister(lister SDKVersionLister, cacheDir string) *CachingSDKVersionLister {
+	return &CachingSDKVersionLister{
+		lister:   lister,
+		cacheDir: cacheDir,
+	}
+}
+
+func (l *CachingSDKVersionLister) ListReleasesByChannel(ctx context.Context, platform Platform, architecture Architecture) (map[string][]Release, error) {
+	releasesByChannel, _, err := l.ListReleasesByChannelWithStatus(ctx, platform, architecture)
+	return releasesByChannel, err
+}
+
+// ListReleasesByChannelWithStatus works like ListReleasesByChannel, but also returns where the manifest was served from.
+func (l *CachingSDKVersionLister) ListReleasesByChannelWithStatus(ctx context.Context, platform Platform, architecture Architecture) (map[string][]Release, CacheStatus, error) {
+	var releasesByChannel map[string][]Release
+	var status CacheStatus
+	if fetcher, ok := l.lister.(releasesManifestFetcher); ok {
+		allReleasesResp, manifestStatus, err := l.listManifestReleases(ctx, fetcher, platform)
+		if err != nil {
+			return nil, "", err
+		}
+		releasesByChannel, status = groupReleasesByChannel(*allReleasesResp, architecture), manifestStatus
+	} else {
+		var err error
+		releasesByChannel, status, err = l.listReleasesByChannel(ctx, platform, architecture)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	l.mu.Lock()
+	l.lastStatus = status
+	l.mu.Unlock()
+
+	return releasesByChannel, status, nil
+}
+
+// LastStatus returns where the manifest of the latest successful listing was served from.
+func (l *CachingSDKVersionLister) LastStatus() CacheStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastStatus
+}
+
+func (l *CachingSDKVersionLister) listAllReleases(ctx context.Context, platform Platform) (*ReleasesResp, error) {
+	fetcher, ok := l.lister.(releasesManifestFetcher)
+	if !ok {
+		return nil, errAllReleasesNotSupported
+	}
+
+	allReleasesResp, status, err := l.listManifestReleases(ctx, fetcher, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	l.lastStatus = status
+	l.mu.Unlock()
+
+	return allReleasesResp, nil
+}
+
+func (l *CachingSDKVersionLister) listManifestReleases(ctx context.Context, fetcher releasesManifestFetcher, platform Platform) (*ReleasesResp, CacheStatus, error) {
+	cached, err := l.readCache(platform)
+	if err != nil {
+		// A corrupted cache is not fatal, the manifest gets downloaded again.
+		cached = nil
+	}
+
+	metadata := manifestMetadata{}
+	if cached != nil {
+		metadata = cached.Metadata
+	}
+
+	manifest, fetchErr := fetcher.fetchReleasesManifest(ctx, platform, metadata)
+	if fetchErr != nil {
+		if cached == nil || ctx.Err() != nil {
+			return nil, "", fetchErr
+		}
+
+		releases, err := parseReleasesManifest(cached)
+		if err != nil {
+			return nil, "", fetchErr
+		}
+		return releases, CacheStatusStale, nil
+	}
+
+	if manifest.NotModified {
+		releases, err := parseReleasesManifest(cached)
+		if err != nil {
+			return nil, "", err
+		}
+		return releases, CacheStatusRevalidated, nil
+	}
+
+	releases, err := parseReleasesManifest(manifest)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// A read-only or full cache dir is not fatal, the downloaded manifest is still valid.
+	_ = l.writeCache(platform, manifest)
+
+	return releases, CacheStatusFresh, nil
+}
+
+// cachedRelease stores the fields of a listed release, which are not part of the releases manifest.
+type cachedRelease struct {
+	Release Release `json:"release"`
+	BaseURL string  `json:"base_url"`
+	Current bool    `json:"current"`
+}
+
+func (l *CachingSDKVersionLister) listReleasesByChannel(ctx context.Context, platform Platform, architecture Architecture) (map[string][]Release, CacheStatus, error) {
+	releasesByChannel, listErr := l.lister.ListReleasesByChannel(ctx, platform, architecture)
+	if listErr != nil {
+		if ctx.Err() != nil {
+			return nil, "", listErr
+		}
+
+		cached, err := l.readReleasesCache(platform, architecture)
+		if err != nil || cached == nil {
+			return nil, "", listErr
+		}
+		return cached, CacheStatusStale, nil
+	}
+
+	// A read-only or full cache dir is not fatal, the listed releases are still valid.
+	_ = l.writeReleasesCache(platform, architecture, releasesByChannel)
+
+	return releasesByChannel, CacheStatusFresh, nil
+}
+
+func (l *CachingSDKVersionLister) manifestPath(platform Platform) string {
+	return filepath.Join(l.cacheDir, fmt.Sprintf("releases_%s.json", platform))
+}
+
+func (l *CachingSDKVersionLister) metadataPath(platform Platform) string {
+	return l.manifestPath(platform) + ".meta"
+}
+
+func (l *CachingSDKVersionLister) readCache(platform Platform) (*releasesManifest, error) {
+	body, err := os.ReadFile(l.manifestPath(platform))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metadataContent, err := os.ReadFile(l.metadataPath(platform))
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata manifestMetadata
+	if err := json.Unmarshal(metadataContent, &metadata); err != nil {
+		return nil, err
+	}
+
+	return &releasesManifest{Body: body, Metadata: metadata}, nil
+}
+
+func (l *CachingSDKVersionLister) writeCache(platform Platform, manifest *releasesManifest) error {
+	if err := os.MkdirAll(l.cacheDir, 0755); err != nil {
+		return err
+	}
+
+	metadataContent, err := json.Marshal(manifest.Metadata)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomically(l.manifestPath(platform), manifest.Body); err != nil {
+		return err
+	}
+	return writeFileAtomically(l.metadataPath(platform), metadataContent)
+}
+
+func (l *CachingSDKVersionLister) releasesPath(platform Platform, architecture Architecture) string {
+	return filepath.Join(l.cacheDir, fmt.Sprintf("releases_%s_%s.json", platform, architecture))
+}
+
+func (l *CachingSDKVersionLister) readReleasesCache(platform Platform, architecture Architecture) (map[string][]Release, error) {
+	content, err := os.ReadFile(l.releasesPath(platform, architecture))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cached map[string][]cachedRelease
+	if err := json.Unmarshal(content, &cached); err != nil {
+		return nil, err
+	}
+
+	releasesByChannel := map[string][]Release{}
+	for channel, cachedReleases := range cached {
+		for _, cachedRelease := range cachedReleases {
+			release := cachedRelease.Release
+			release.BaseURL = cachedRelease.BaseURL
+			release.Current = cachedRelease.Current
+			releasesByChannel[channel] = append(releasesByChannel[channel], release)
+		}
+	}
+	return releasesByChannel, nil
+}
+
+func (l *CachingSDKVersionLister) writeReleasesCache(platform Platform, architecture Architecture, releasesByChannel map[string][]Release) error {
+	if err := os.MkdirAll(l.cacheDir, 0755); err != nil {
+		return err
+	}
+
+	cached := map[string][]cachedRelease{}
+	for channel, releases := range releasesByChannel {
+		for _, release := range releases {
+			cached[channel] = append(cached[channel], cachedRelease{Release: release, BaseURL: release.BaseURL, Current: release.Current})
+		}
+	}
+
+	content, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(l.releasesPath(platform, architecture), content)
+}
+
+func writeFileAtomically(pth string, content []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(pth), filepath.Base(pth)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(content); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+
+	if err := os.Rename(f.Name(), pth); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package fluttersdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingSDKVersionLister_ListReleasesByChannelWithStatus(t *testing.T) {
+	const etag = `"manifest-v1"`
+	requestCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, err := w.Write([]byte(flutterSDKsResponse))
+		require.NoError(t, err)
+	}))
+
+	lister := NewCachingSDKVersionLister(NewSDKVersionLister(WithStorageBaseURLs(ts.URL)), t.TempDir())
+
+	releases, status, err := lister.ListReleasesByChannelWithStatus(context.Background(), MacOS, ARM64)
+	require.NoError(t, err)
+	require.Equal(t, CacheStatusFresh, status)
+	require.Equal(t, "3.13.9", releases["stable"][0].Version)
+
+	releases, status, err = lister.ListReleasesByChannelWithStatus(context.Background(), MacOS, ARM64)
+	require.NoError(t, err)
+	require.Equal(t, CacheStatusRevalidated, status)
+	require.Equal(t, "3.13.9", releases["stable"][0].Version)
+	require.Equal(t, 2, requestCount)
+
+	ts.Close()
+
+	releases, err = lister.ListReleasesByChannel(context.Background(), MacOS, ARM64)
+	require.NoError(t, err)
+	require.Equal(t, CacheStatusStale, lister.LastStatus())
+	require.Equal(t, "3.13.9", releases["stable"][0].Version)
+
+	_, _, err = lister.ListReleasesByChannelWithStatus(context.Background(), Linux, X64)
+	require.Error(t, err)
+}
+
+func TestCachingSDKVersionLister_ReadOnlyCacheDir(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(flutterSDKsResponse))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	// The cache dir can't be created, because its parent is a file.
+	parent := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(parent, nil, 0644))
+
+	lister := NewCachingSDKVersionLister(NewSDKVersionLister(WithStorageBaseURLs(ts.URL)), filepath.Join(parent, "cache"))
+	releases, status, err := lister.ListReleasesByChannelWithStatus(context.Background(), MacOS, ARM64)
+	require.NoError(t, err)
+	require.Equal(t, CacheStatusFresh, status)
+	require.Equal(t, "3.13.9", releases["stable"][0].Version)
+}
+
+type failingSDKVersionLister struct {
+	releasesByChannel map[string][]Release
+	err               error
+}
+
+func (l *failingSDKVersionLister) ListReleasesByChannel(ctx context.Context, platform Platform, architecture Architecture) (map[string][]Release, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.releasesByChannel, nil
+}
+
+func TestCachingSDKVersionLister_CustomLister(t *testing.T) {
+	release := Release{
+		Hash:        "abc",
+		Channel:     "stable",
+		Version:     "3.13.9",
+		ReleaseDate: time.Date(2023, 10, 25, 0, 0, 0, 0, time.UTC),
+		BaseURL:     "https://mirror.example.com/flutter_infra_release/releases",
+		Current:     true,
+	}
+	customLister := &failingSDKVersionLister{releasesByChannel: map[string][]Release{"stable": {release}}}
+	lister := NewCachingSDKVersionLister(customLister, t.TempDir())
+
+	releases, status, err := lister.ListReleasesByChannelWithStatus(context.Background(), MacOS, ARM64)
+	require.NoError(t, err)
+	require.Equal(t, CacheStatusFresh, status)
+	require.Equal(t, map[string][]Release{"stable": {release}}, releases)
+
+	customLister.err = errors.New("offline")
+
+	releases, status, err = lister.ListReleasesByChannelWithStatus(context.Background(), MacOS, ARM64)
+	require.NoError(t, err)
+	require.Equal(t, CacheStatusStale, status)
+	require.Equal(t, map[string][]Release{"stable": {release}}, releases)
+
+	_, _, err = lister.ListReleasesByChannelWithStatus(context.Background(), MacOS, X64)
+	require.EqualError(t, err, "offline")
+}
@@ -0,0 +1,415 @@
+package fluttersdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// CatalogRelease is a release of a ReleaseCatalog with its pre-parsed versions.
+// DartVersion is nil for releases without a (valid) Dart SDK version, these only match queries without Dart SDK requirements.
+type CatalogRelease struct {
+	Release
+	FlutterVersion *semver.Version
+	DartVersion    *semver.Version
+}
+
+// CatalogWarning describes a manifest entry, which was skipped or partially parsed when building a ReleaseCatalog.
+type CatalogWarning struct {
+	Release Release
+	Err     error
+}
+
+func (w CatalogWarning) String() string {
+	return fmt.Sprintf("%s release %s (%s): %s", w.Release.Channel, w.Release.Version, w.Release.Hash, w.Err)
+}
+
+/*
+ReleaseCatalog indexes the releases of a single platform and architecture for repeated queries.
+
+Versions are parsed once, when building the catalog, and the releases of every channel are kept in descending version order.
+Entries with an invalid Flutter version are skipped, entries with an invalid Dart SDK version are kept without a Dart version,
+both of them are reported as warnings.
+*/
+type ReleaseCatalog struct {
+	releases  []CatalogRelease
+	byChannel map[string][]int
+	byHash    map[string][]int
+	byVersion map[string][]int
+	current   map[string]int
+	warnings  []CatalogWarning
+}
+
+// NewReleaseCatalog builds the catalog of the manifest's releases for the given architecture.
+func NewReleaseCatalog(resp ReleasesResp, architecture Architecture) *ReleaseCatalog {
+	return newReleaseCatalog(architectureReleases(resp, architecture))
+}
+
+// ReleaseCatalog lists the releases of the platform and architecture and builds their catalog,
+// finders created by NewSDKVersionFinder reuse the catalog until it is older than the catalog TTL (see WithCatalogTTL).
+func (f SDKVersionFinder) ReleaseCatalog(ctx context.Context, platform Platform, architecture Architecture) (*ReleaseCatalog, error) {
+	if f.catalogs == nil {
+		return f.listReleaseCatalog(ctx, platform, architecture)
+	}
+
+	key := catalogKey{platform: platform, architecture: architecture}
+	if catalog := f.catalogs.get(key); catalog != nil {
+		return catalog, nil
+	}
+
+	catalog, err := f.listReleaseCatalog(ctx, platform, architecture)
+	if err != nil {
+		return nil, err
+	}
+	f.catalogs.set(key, catalog)
+	return catalog, nil
+}
+
+func (f SDKVersionFinder) listReleaseCatalog(ctx context.Context, platform Platform, architecture Architecture) (*ReleaseCatalog, error) {
+	releasesByChannel, err := f.SDKVersionLister.ListReleasesByChannel(ctx, platform, architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []Release
+	for _, channel := range sortedChannelNames(releasesByChannel) {
+		releases = append(releases, releasesByChannel[channel]...)
+	}
+	return newReleaseCatalog(releases), nil
+}
+
+type catalogKey struct {
+	platform     Platform
+	architecture Architecture
+}
+
+type cachedCatalog struct {
+	catalog *ReleaseCatalog
+	builtAt time.Time
+}
+
+// catalogCache memoizes the release catalogs of an SDKVersionFinder for ttl, it is shared by the copies of the finder.
+type catalogCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	catalogs map[catalogKey]cachedCatalog
+}
+
+func newCatalogCache(ttl time.Duration) *catalogCache {
+	return &catalogCache{ttl: ttl, now: time.Now, catalogs: map[catalogKey]cachedCatalog{}}
+}
+
+func (c *catalogCache) get(key catalogKey) *ReleaseCatalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.catalogs[key]
+	if !ok || c.now().Sub(cached.builtAt) >= c.ttl {
+		return nil
+	}
+	return cached.catalog
+}
+
+func (c *catalogCache) set(key catalogKey, catalog *ReleaseCatalog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.catalogs[key] = cachedCatalog{catalog: catalog, builtAt: c.now()}
+}
+
+func (c *catalogCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.catalogs = map[catalogKey]cachedCatalog{}
+}
+
+func newReleaseCatalog(releases []Release) *ReleaseCatalog {
+	c := &ReleaseCatalog{
+		byChannel: map[string][]int{},
+		byHash:    map[string][]int{},
+		byVersion: map[string][]int{},
+		current:   map[string]int{},
+	}
+
+	for _, release := range releases {
+		flutterVersion, err := semver.NewVersion(release.Version)
+		if err != nil {
+			c.warnings = append(c.warnings, CatalogWarning{Release: release, Err: fmt.Errorf("invalid Flutter version: %w", err)})
+			continue
+		}
+
+		var dartVersion *semver.Version
+		if release.DartSdkVersion != "" {
+			dartVersion, err = ParseDartSDKVersion(release.DartSdkVersion)
+			if err != nil {
+				c.warnings = append(c.warnings, CatalogWarning{Release: release, Err: fmt.Errorf("invalid Dart SDK version: %w", err)})
+			}
+		}
+
+		idx := len(c.releases)
+		c.releases = append(c.releases, CatalogRelease{Release: release, FlutterVersion: flutterVersion, DartVersion: dartVersion})
+		c.byChannel[release.Channel] = append(c.byChannel[release.Channel], idx)
+		if release.Hash != "" {
+			hash := strings.ToLower(release.Hash)
+			c.byHash[hash] = append(c.byHash[hash], idx)
+		}
+		c.byVersion[flutterVersion.String()] = append(c.byVersion[flutterVersion.String()], idx)
+		if _, ok := c.current[release.Channel]; release.Current && !ok {
+			c.current[release.Channel] = idx
+		}
+	}
+
+	for _, indexes := range c.byChannel {
+		c.sortByVersion(indexes)
+	}
+
+	return c
+}
+
+// sortByVersion sorts the release indexes in descending version order, releases with the same version keep their order.
+func (c *ReleaseCatalog) sortByVersion(indexes []int) {
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return c.releases[indexes[j]].FlutterVersion.LessThan(c.releases[indexes[i]].FlutterVersion)
+	})
+}
+
+func (c *ReleaseCatalog) Warnings() []CatalogWarning {
+	return c.warnings
+}
+
+// Releases returns the channel's releases in descending version order.
+func (c *ReleaseCatalog) Releases(channel Channel) []CatalogRelease {
+	return c.releasesAt(c.byChannel[string(channel)])
+}
+
+// Latest returns the release picked by the query's strategy (the latest one by default) from the matching releases,
+// if channel is empty, stable, beta and dev channels are checked in this order.
+func (c *ReleaseCatalog) Latest(channel Channel, query SDKQuery) *CatalogRelease {
+	channels := []Channel{channel}
+	if channel == "" {
+		channels = []Channel{Stable, Beta, Dev}
+	}
+
+	for _, ch := range channels {
+		var matching []CatalogRelease
+		for _, idx := range c.byChannel[string(ch)] {
+			if query.matchesVersions(c.releases[idx].FlutterVersion, c.releases[idx].DartVersion) {
+				matching = append(matching, c.releases[idx])
+			}
+		}
+
+		if release := query.Strategy.pick(matching, query.InstalledFlutterVersions); release != nil {
+			return release
+		}
+	}
+
+	return nil
+}
+
+// Find returns every release matching the query and the filter in descending version order.
+// Releases with the same version are ordered by the filter's channel order.
+func (c *ReleaseCatalog) Find(query SDKQuery, filter ReleaseFilter) []CatalogRelease {
+	var indexes []int
+	for _, channel := range filter.channels() {
+		for _, idx := range c.byChannel[string(channel)] {
+			release := c.releases[idx]
+			if filter.includes(release.Release) && query.matchesVersions(release.FlutterVersion, release.DartVersion) {
+				indexes = append(indexes, idx)
+			}
+		}
+	}
+
+	c.sortByVersion(indexes)
+	return c.releasesAt(indexes)
+}
+
+// ByHash returns the releases built from the given framework commit, in catalog order.
+// Catalogs built by SDKVersionFinder.ReleaseCatalog list the channels in stable, beta, dev order.
+func (c *ReleaseCatalog) ByHash(hash string) []CatalogRelease {
+	return c.releasesAt(c.byHash[strings.ToLower(hash)])
+}
+
+// ByVersion returns the releases of the given Flutter version, in catalog order.
+func (c *ReleaseCatalog) ByVersion(version *semver.Version) []CatalogRelease {
+	if version == nil {
+		return nil
+	}
+	return c.releasesAt(c.byVersion[version.String()])
+}
+
+// ChannelHead returns the channel's current release.
+func (c *ReleaseCatalog) ChannelHead(channel Channel) *CatalogRelease {
+	idx, ok := c.current[string(channel)]
+	if !ok {
+		return nil
+	}
+	release := c.releases[idx]
+	return &release
+}
+
+func (c *ReleaseCatalog) noMatchingReleaseError(platform Platform, architecture Architecture, channels []Channel, query SDKQuery) NoMatchingReleaseError {
+	err := NoMatchingReleaseError{
+		Platform:     platform,
+		Architecture: architecture,
+		Channels:     channels,
+		Query:        query,
+		Warnings:     c.warnings,
+	}
+
+	below, above := c.closest(channels, query)
+	if below != nil {
+		err.Below = &below.Release
+	}
+	if above != nil {
+		err.Above = &above.Release
+	}
+
+	return err
+}
+
+/*
+closest returns the releases of the channels closest to the requested version of the SDK, which has no matching release:
+the Flutter version if no release satisfies the Flutter requirement (or there is no Dart requirement), the Dart SDK version otherwise.
+*/
+func (c *ReleaseCatalog) closest(channels []Channel, query SDKQuery) (*CatalogRelease, *CatalogRelease) {
+	flutterVersion := func(release CatalogRelease) *semver.Version {
+		return release.FlutterVersion
+	}
+	dartVersion := func(release CatalogRelease) *semver.Version {
+		return release.DartVersion
+	}
+
+	requestedFlutter := requestedVersion(query.FlutterVersion, query.FlutterVersionConstraint)
+	requestedDart := requestedVersion(query.DartVersion, query.DartVersionConstraint)
+
+	version, requested := flutterVersion, requestedFlutter
+	if requestedDart != nil && (requestedFlutter == nil || c.anyMatches(channels, SDKQuery{FlutterVersion: query.FlutterVersion, FlutterVersionConstraint: query.FlutterVersionConstraint})) {
+		version, requested = dartVersion, requestedDart
+	}
+	if requested == nil {
+		return nil, nil
+	}
+
+	var below, above *CatalogRelease
+	for _, channel := range channels {
+		for _, idx := range c.byChannel[string(channel)] {
+			release := c.releases[idx]
+			v := version(release)
+			if v == nil {
+				continue
+			}
+
+			if v.LessThan(requested) && (below == nil || version(*below).LessThan(v)) {
+				below = &release
+			}
+			if v.GreaterThan(requested) && (above == nil || v.LessThan(version(*above))) {
+				above = &release
+			}
+		}
+	}
+
+	return below, above
+}
+
+func (c *ReleaseCatalog) anyMatches(channels []Channel, query SDKQuery) bool {
+	for _, channel := range channels {
+		for _, idx := range c.byChannel[string(channel)] {
+			if query.matchesVersions(c.releases[idx].FlutterVersion, c.releases[idx].DartVersion) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// minVersionConstraint is implemented by constraints, which know their lowest bound, like the pub constraints of flutterproject.
+type minVersionConstraint interface {
+	MinVersion() *semver.Version
+}
+
+// requestedVersion returns the exact version, or the lowest bound of the constraint.
+func requestedVersion(version *semver.Version, constraint VersionConstraint) *semver.Version {
+	if version != nil {
+		return version
+	}
+	if constraint == nil {
+		return nil
+	}
+	if c, ok := constraint.(minVersionConstraint); ok {
+		return c.MinVersion()
+	}
+	return constraintMinVersion(constraint.String())
+}
+
+/*
+constraintMinVersion returns the lowest bound of a Masterminds semver constraint, like 3.20.0 for "<3.22.0 >=3.20.0".
+The lower bound of a group is its highest >, >=, =, ^ or ~ bound, the lowest bound of the || separated groups is returned.
+*/
+func constraintMinVersion(constraint string) *semver.Version {
+	var lowest *semver.Version
+	for _, group := range strings.Split(constraint, "||") {
+		var groupMin *semver.Version
+		tokens := strings.FieldsFunc(group, func(r rune) bool { return r == ' ' || r == ',' })
+		for i := 0; i < len(tokens); i++ {
+			token := tokens[i]
+			if token == "-" {
+				// the upper bound of a hyphen range (1.2 - 1.4)
+				i++
+				continue
+			}
+			if strings.HasPrefix(token, "<") || strings.HasPrefix(token, "!=") {
+				continue
+			}
+
+			v, err := semver.NewVersion(strings.TrimLeft(token, ">=^~v"))
+			if err != nil {
+				continue
+			}
+			if groupMin == nil || v.GreaterThan(groupMin) {
+				groupMin = v
+			}
+		}
+
+		if groupMin != nil && (lowest == nil || groupMin.LessThan(lowest)) {
+			lowest = groupMin
+		}
+	}
+	return lowest
+}
+
+func (c *ReleaseCatalog) releasesAt(indexes []int) []CatalogRelease {
+	var releases []CatalogRelease
+	for _, idx := range indexes {
+		releases = append(releases, c.releases[idx])
+	}
+	return releases
+}
+
+// sortedChannelNames returns the channels in the default channel order (stable, beta, dev), followed by any other channel alphabetically.
+func sortedChannelNames(releasesByChannel map[string][]Release) []string {
+	rank := func(channel string) int {
+		for i, c := range []Channel{Stable, Beta, Dev} {
+			if string(c) == channel {
+				return i
+			}
+		}
+		return 3
+	}
+
+	var channels []string
+	for channel := range releasesByChannel {
+		channels = append(channels, channel)
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		if rank(channels[i]) != rank(channels[j]) {
+			return rank(channels[i]) < rank(channels[j])
+		}
+		return channels[i] < channels[j]
+	})
+	return channels
+}
@@ -0,0 +1,202 @@
+package fluttersdk
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/require"
+)
+
+const catalogReleasesResponse = `{
+	"base_url": "https://storage.googleapis.com/flutter_infra_release/releases",
+	"current_release": {
+		"beta": "ff5b5b5fa6f35b717667719ddfdb1521d8bdd05a",
+		"dev": "13a2fb10b838971ce211230f8ffdd094c14af02c",
+		"stable": "d211f42860350d914a5ad8102f9ec32764dc6d06"
+	},
+	"releases": [
+		{"hash": "d211f42860350d914a5ad8102f9ec32764dc6d06", "channel": "stable", "version": "3.13.9", "dart_sdk_version": "3.1.5", "dart_sdk_arch": "arm64"},
+		{"hash": "d211f42860350d914a5ad8102f9ec32764dc6d06", "channel": "stable", "version": "3.13.9", "dart_sdk_version": "3.1.5", "dart_sdk_arch": "x64"},
+		{"hash": "ff5b5b5fa6f35b717667719ddfdb1521d8bdd05a", "channel": "beta", "version": "3.16.0-0.5.pre", "dart_sdk_version": "3.2.0 (build 3.2.0-210.4.beta)", "dart_sdk_arch": "arm64"},
+		{"hash": "6c4930c4ac86fb286f30e31d0ec8bffbcbb9953e", "channel": "stable", "version": "3.13.8", "dart_sdk_version": "3.1.4", "dart_sdk_arch": "arm64"},
+		{"hash": "d211f42860350d914a5ad8102f9ec32764dc6d06", "channel": "beta", "version": "3.13.9", "dart_sdk_version": "3.1.5", "dart_sdk_arch": "arm64"},
+		{"hash": "2f708eb8396e362e280fac22cf171c2cb467343c", "channel": "stable", "version": "3.13.7", "dart_sdk_version": "not a version", "dart_sdk_arch": "arm64"},
+		{"hash": "0000000000000000000000000000000000000000", "channel": "stable", "version": "broken", "dart_sdk_version": "3.1.0", "dart_sdk_arch": "arm64"},
+		{"hash": "13a2fb10b838971ce211230f8ffdd094c14af02c", "channel": "dev", "version": "2.13.0-0.1.pre", "dart_sdk_version": "2.17.0 (build 2.17.0-266.1.beta)", "dart_sdk_arch": "arm64"}
+	]
+}`
+
+func newTestReleaseCatalog(t *testing.T) *ReleaseCatalog {
+	var resp ReleasesResp
+	require.NoError(t, json.Unmarshal([]byte(catalogReleasesResponse), &resp))
+	return NewReleaseCatalog(resp, ARM64)
+}
+
+func catalogReleaseNames(releases []CatalogRelease) []string {
+	var names []string
+	for _, release := range releases {
+		names = append(names, release.Version+"@"+release.Channel)
+	}
+	return names
+}
+
+func TestNewReleaseCatalog(t *testing.T) {
+	catalog := newTestReleaseCatalog(t)
+
+	var warnings []string
+	for _, warning := range catalog.Warnings() {
+		warnings = append(warnings, warning.String())
+	}
+	require.Equal(t, []string{
+		"stable release 3.13.7 (2f708eb8396e362e280fac22cf171c2cb467343c): invalid Dart SDK version: Invalid Semantic Version",
+		"stable release broken (0000000000000000000000000000000000000000): invalid Flutter version: Invalid Semantic Version",
+	}, warnings)
+
+	stable := catalog.Releases(Stable)
+	require.Equal(t, []string{"3.13.9@stable", "3.13.8@stable", "3.13.7@stable"}, catalogReleaseNames(stable))
+	require.Equal(t, "3.1.5", stable[0].DartVersion.String())
+	require.Equal(t, "https://storage.googleapis.com/flutter_infra_release/releases", stable[0].BaseURL)
+	require.Nil(t, stable[2].DartVersion)
+
+	require.Equal(t, []string{"3.16.0-0.5.pre@beta", "3.13.9@beta"}, catalogReleaseNames(catalog.Releases(Beta)))
+	require.Equal(t, "2.17.0", catalog.Releases(Dev)[0].DartVersion.String())
+}
+
+func TestReleaseCatalog_Lookups(t *testing.T) {
+	catalog := newTestReleaseCatalog(t)
+
+	require.Equal(t, []string{"3.13.9@stable", "3.13.9@beta"}, catalogReleaseNames(catalog.ByHash("D211F42860350D914A5AD8102F9EC32764DC6D06")))
+	require.Empty(t, catalog.ByHash("0000000000000000000000000000000000000000"))
+
+	require.Equal(t, []string{"3.13.9@stable", "3.13.9@beta"}, catalogReleaseNames(catalog.ByVersion(semver.MustParse("3.13.9"))))
+	require.Empty(t, catalog.ByVersion(semver.MustParse("3.13.6")))
+
+	require.Equal(t, "3.13.9", catalog.ChannelHead(Stable).Version)
+	require.Equal(t, "3.16.0-0.5.pre", catalog.ChannelHead(Beta).Version)
+	require.Nil(t, catalog.ChannelHead("master"))
+}
+
+func TestReleaseCatalog_Latest(t *testing.T) {
+	tests := []struct {
+		name        string
+		channel     Channel
+		query       SDKQuery
+		wantRelease string
+	}{
+		{
+			name:        "Latest stable release",
+			wantRelease: "3.13.9@stable",
+		},
+		{
+			name:        "Latest beta release",
+			channel:     Beta,
+			wantRelease: "3.16.0-0.5.pre@beta",
+		},
+		{
+			name:        "Dart version",
+			query:       SDKQuery{DartVersion: semver.MustParse("3.1.4")},
+			wantRelease: "3.13.8@stable",
+		},
+		{
+			name:        "Falls back to the next channel",
+			query:       SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.14.0-0")},
+			wantRelease: "3.16.0-0.5.pre@beta",
+		},
+		{
+			name:  "Release with invalid Dart version doesn't match Dart requirements",
+			query: SDKQuery{FlutterVersion: semver.MustParse("3.13.7"), DartVersionConstraint: mustNewConstraint(t, ">=3.0.0")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTestReleaseCatalog(t).Latest(tt.channel, tt.query)
+			if tt.wantRelease == "" {
+				require.Nil(t, got)
+				return
+			}
+			require.Equal(t, tt.wantRelease, got.Version+"@"+got.Channel)
+		})
+	}
+}
+
+func TestReleaseCatalog_Find(t *testing.T) {
+	catalog := newTestReleaseCatalog(t)
+
+	got := catalog.Find(SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.13.8-0")}, ReleaseFilter{})
+	require.Equal(t, []string{"3.16.0-0.5.pre@beta", "3.13.9@stable", "3.13.9@beta", "3.13.8@stable"}, catalogReleaseNames(got))
+
+	got = catalog.Find(SDKQuery{}, ReleaseFilter{Channels: []Channel{Dev, Beta}})
+	require.Equal(t, []string{"3.16.0-0.5.pre@beta", "3.13.9@beta", "2.13.0-0.1.pre@dev"}, catalogReleaseNames(got))
+}
+
+type countingSDKVersionLister struct {
+	releasesByChannel map[string][]Release
+	count             int
+}
+
+func (l *countingSDKVersionLister) ListReleasesByChannel(ctx context.Context, platform Platform, architecture Architecture) (map[string][]Release, error) {
+	l.count++
+	return l.releasesByChannel, nil
+}
+
+func TestSDKVersionFinder_ReleaseCatalog(t *testing.T) {
+	lister := &countingSDKVersionLister{releasesByChannel: map[string][]Release{"stable": {
+		{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5", Hash: "d211f42860350d914a5ad8102f9ec32764dc6d06"},
+		{Channel: "stable", Version: "broken", DartSdkVersion: "3.1.0", Hash: "0000000000000000000000000000000000000000"},
+	}}}
+	f := NewSDKVersionFinder(WithSDKVersionLister(lister))
+
+	release, err := f.FindLatestReleaseFor(MacOS, ARM64, Stable, SDKQuery{})
+	require.NoError(t, err)
+	require.Equal(t, "3.13.9", release.Version)
+	release, err = f.FindReleaseByHash(MacOS, ARM64, "d211f42860350d914a5ad8102f9ec32764dc6d06")
+	require.NoError(t, err)
+	require.Equal(t, "3.13.9", release.Version)
+	require.Equal(t, 1, lister.count)
+
+	_, err = f.FindLatestReleaseFor(MacOS, X64, Stable, SDKQuery{FlutterVersion: semver.MustParse("3.13.8")})
+	require.EqualError(t, err, "no Flutter SDK release matches Flutter 3.13.8 on macos x64 (channels: stable), did you mean 3.13.9 (stable, Dart 3.1.5)?"+
+		" (invalid manifest entries: stable release broken (0000000000000000000000000000000000000000): invalid Flutter version: Invalid Semantic Version)")
+	require.Equal(t, 2, lister.count)
+
+	literal := SDKVersionFinder{SDKVersionLister: lister}
+	_, err = literal.FindLatestReleaseFor(MacOS, ARM64, Stable, SDKQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 3, lister.count)
+
+	f.InvalidateCatalogs()
+	_, err = f.FindLatestReleaseFor(MacOS, ARM64, Stable, SDKQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 4, lister.count)
+}
+
+func TestSDKVersionFinder_ReleaseCatalog_TTL(t *testing.T) {
+	lister := &countingSDKVersionLister{releasesByChannel: map[string][]Release{"stable": {
+		{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5"},
+	}}}
+	now := time.Now()
+	f := NewSDKVersionFinder(WithSDKVersionLister(lister), WithCatalogTTL(time.Minute))
+	f.catalogs.now = func() time.Time { return now }
+
+	_, err := f.FindLatestReleaseFor(MacOS, ARM64, Stable, SDKQuery{})
+	require.NoError(t, err)
+	now = now.Add(59 * time.Second)
+	_, err = f.FindLatestReleaseFor(MacOS, ARM64, Stable, SDKQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 1, lister.count)
+
+	now = now.Add(time.Second)
+	_, err = f.FindLatestReleaseFor(MacOS, ARM64, Stable, SDKQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 2, lister.count)
+
+	uncached := NewSDKVersionFinder(WithSDKVersionLister(lister), WithCatalogTTL(0))
+	_, err = uncached.FindLatestReleaseFor(MacOS, ARM64, Stable, SDKQuery{})
+	require.NoError(t, err)
+	_, err = uncached.FindLatestReleaseFor(MacOS, ARM64, Stable, SDKQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 4, lister.count)
+}
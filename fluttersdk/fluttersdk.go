@@ -1,11 +1,15 @@
 package fluttersdk
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"regexp"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
@@ -26,6 +30,8 @@ const (
 	ARM64 Architecture = "arm64"
 )
 
+var architectures = []Architecture{X64, ARM64}
+
 type Release struct {
 	Hash           string    `json:"hash"`
 	Channel        string    `json:"channel"`
@@ -35,6 +41,23 @@ type Release struct {
 	ReleaseDate    time.Time `json:"release_date"`
 	Archive        string    `json:"archive"`
 	Sha256         string    `json:"sha256"`
+	// BaseURL is the base_url of the releases manifest the release was listed from, Archive is relative to it.
+	BaseURL string `json:"-"`
+	// Current tells whether the release is the head of its channel (the channel's current_release in the releases manifest).
+	Current bool `json:"-"`
+}
+
+/*
+Architecture returns the architecture the release was built for.
+
+Older manifest entries (and every entry of some platforms' manifests) have no dart_sdk_arch,
+these releases were only built for x64, so X64 is returned for them.
+*/
+func (r Release) Architecture() Architecture {
+	if r.DartSdkArch == "" {
+		return X64
+	}
+	return Architecture(r.DartSdkArch)
 }
 
 type ReleasesResp struct {
@@ -47,6 +70,19 @@ type ReleasesResp struct {
 	Releases []Release `json:"releases"`
 }
 
+func (r ReleasesResp) currentReleaseOf(channel string) string {
+	switch Channel(channel) {
+	case Stable:
+		return r.CurrentRelease.Stable
+	case Beta:
+		return r.CurrentRelease.Beta
+	case Dev:
+		return r.CurrentRelease.Dev
+	default:
+		return ""
+	}
+}
+
 type Channel string
 
 const (
@@ -55,165 +91,602 @@ const (
 	Dev    Channel = "dev"
 )
 
+// VersionConstraint is a Flutter or Dart SDK version constraint, like a *semver.Constraints.
+type VersionConstraint interface {
+	Check(version *semver.Version) bool
+	String() string
+}
+
 type SDKQuery struct {
 	FlutterVersion           *semver.Version
-	FlutterVersionConstraint *semver.Constraints
+	FlutterVersionConstraint VersionConstraint
 	DartVersion              *semver.Version
-	DartVersionConstraint    *semver.Constraints
+	DartVersionConstraint    VersionConstraint
+
+	// Strategy decides which of the matching releases FindLatestReleaseFor picks, defaults to Newest.
+	Strategy ResolutionStrategy
+	// InstalledFlutterVersions are the Flutter SDK versions available on the machine, used by the PreferInstalled strategy.
+	InstalledFlutterVersions []*semver.Version
+}
+
+/*
+NoMatchingReleaseError is returned when no release of the searched channels satisfies the requirements.
+
+Below and Above are the releases closest to the requested version of the SDK without a matching release: the Flutter version
+if no release satisfies the Flutter requirement, the Dart SDK version if only the Dart requirement can't be satisfied.
+For constraints the lowest bound of the constraint (like 3.22.0 for ^3.22.0) is used.
+Commit is set for commit pins, ChannelHead for channel head pins.
+Warnings are the manifest entries skipped or partially parsed by the catalog, one of them might be the release the query was looking for.
+*/
+type NoMatchingReleaseError struct {
+	Platform     Platform
+	Architecture Architecture
+	Channels     []Channel
+	Query        SDKQuery
+	Commit       string
+	ChannelHead  bool
+	Below        *Release
+	Above        *Release
+	Warnings     []CatalogWarning
+}
+
+func (e NoMatchingReleaseError) Error() string {
+	var channels []string
+	for _, channel := range e.Channels {
+		channels = append(channels, string(channel))
+	}
+
+	msg := fmt.Sprintf("no Flutter SDK release matches %s on %s %s (channels: %s)", e.requirement(), e.Platform, e.Architecture, strings.Join(channels, ", "))
+
+	var suggestions []string
+	for _, release := range e.Suggestions() {
+		suggestions = append(suggestions, fmt.Sprintf("%s (%s, Dart %s)", release.Version, release.Channel, release.DartSdkVersion))
+	}
+	if len(suggestions) > 0 {
+		msg += fmt.Sprintf(", did you mean %s?", strings.Join(suggestions, " or "))
+	}
+
+	var warnings []string
+	for _, warning := range e.Warnings {
+		warnings = append(warnings, warning.String())
+	}
+	if len(warnings) > 0 {
+		msg += fmt.Sprintf(" (invalid manifest entries: %s)", strings.Join(warnings, "; "))
+	}
+
+	return msg
+}
+
+// Suggestions returns the closest releases, the one below the requested version first.
+func (e NoMatchingReleaseError) Suggestions() []Release {
+	var releases []Release
+	if e.Below != nil {
+		releases = append(releases, *e.Below)
+	}
+	if e.Above != nil {
+		releases = append(releases, *e.Above)
+	}
+	return releases
 }
 
+func (e NoMatchingReleaseError) requirement() string {
+	var requirements []string
+	switch {
+	case e.Commit != "":
+		requirements = append(requirements, "commit "+e.Commit)
+	case e.ChannelHead && len(e.Channels) == 1:
+		requirements = append(requirements, fmt.Sprintf("the head of the %s channel", e.Channels[0]))
+	case e.ChannelHead:
+		requirements = append(requirements, "the head of the channel")
+	case e.Query.FlutterVersion != nil:
+		requirements = append(requirements, "Flutter "+e.Query.FlutterVersion.String())
+	case e.Query.FlutterVersionConstraint != nil:
+		requirements = append(requirements, "Flutter "+e.Query.FlutterVersionConstraint.String())
+	}
+
+	if e.Query.DartVersion != nil {
+		requirements = append(requirements, "Dart "+e.Query.DartVersion.String())
+	} else if e.Query.DartVersionConstraint != nil {
+		requirements = append(requirements, "Dart "+e.Query.DartVersionConstraint.String())
+	}
+
+	if len(requirements) == 0 {
+		return "any version"
+	}
+	return strings.Join(requirements, " and ")
+}
+
+/*
+SDKVersionFinder looks up releases in the release catalogs of the listed releases.
+
+Finders created by NewSDKVersionFinder build the catalog of a platform and architecture once and reuse it for the lookups
+of the next DefaultCatalogTTL (see WithCatalogTTL), long-lived finders see new releases once the catalog expires
+or after InvalidateCatalogs. Listers revalidating their cache (like CachingSDKVersionLister) are only called when the catalog is rebuilt.
+Finders created as a struct literal list the releases on every lookup.
+*/
 type SDKVersionFinder struct {
 	SDKVersionLister SDKVersionLister
+
+	catalogs *catalogCache
 }
 
-func NewSDKVersionFinder() SDKVersionFinder {
-	return SDKVersionFinder{SDKVersionLister: NewSDKVersionLister()}
+// DefaultCatalogTTL is how long finders created by NewSDKVersionFinder reuse a release catalog by default.
+const DefaultCatalogTTL = 10 * time.Minute
+
+type SDKVersionFinderOption func(*SDKVersionFinder)
+
+// WithSDKVersionLister sets the lister the releases are listed with, defaults to NewSDKVersionLister().
+func WithSDKVersionLister(lister SDKVersionLister) SDKVersionFinderOption {
+	return func(f *SDKVersionFinder) {
+		f.SDKVersionLister = lister
+	}
+}
+
+// WithCatalogTTL sets how long a release catalog is reused, zero or a negative TTL disables reusing catalogs.
+func WithCatalogTTL(ttl time.Duration) SDKVersionFinderOption {
+	return func(f *SDKVersionFinder) {
+		f.catalogs.ttl = ttl
+	}
+}
+
+func NewSDKVersionFinder(opts ...SDKVersionFinderOption) SDKVersionFinder {
+	f := SDKVersionFinder{catalogs: newCatalogCache(DefaultCatalogTTL)}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	if f.SDKVersionLister == nil {
+		f.SDKVersionLister = NewSDKVersionLister()
+	}
+	return f
+}
+
+// InvalidateCatalogs drops the reused release catalogs of the finder and its copies, the next lookups list the releases again.
+func (f SDKVersionFinder) InvalidateCatalogs() {
+	if f.catalogs != nil {
+		f.catalogs.clear()
+	}
 }
 
 func (f SDKVersionFinder) FindLatestReleaseFor(platform Platform, architecture Architecture, channel Channel, query SDKQuery) (*Release, error) {
-	releasesByChannel, err := f.SDKVersionLister.ListReleasesByChannel(platform, architecture)
-	if err != nil {
+	return f.FindLatestReleaseForContext(context.Background(), platform, architecture, channel, query)
+}
+
+// FindLatestReleaseForContext returns the release picked by the query's strategy (the latest one by default) from the matching releases,
+// if channel is empty, stable, beta and dev channels are checked in this order.
+// Returns a NoMatchingReleaseError if none of the releases match, and an error if the query's strategy is unknown.
+func (f SDKVersionFinder) FindLatestReleaseForContext(ctx context.Context, platform Platform, architecture Architecture, channel Channel, query SDKQuery) (*Release, error) {
+	if err := query.Strategy.Validate(); err != nil {
 		return nil, err
 	}
 
-	if channel != "" {
-		releases := releasesByChannel[string(channel)]
-		return findLatestReleaseFor(releases, query)
+	catalog, err := f.ReleaseCatalog(ctx, platform, architecture)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, c := range []Channel{Stable, Beta, Dev} {
-		releases := releasesByChannel[string(c)]
-		release, err := findLatestReleaseFor(releases, query)
-		if err != nil {
-			return nil, err
-		}
-		if release != nil {
-			return release, nil
+	release := catalog.Latest(channel, query)
+	if release == nil {
+		channels := []Channel{channel}
+		if channel == "" {
+			channels = []Channel{Stable, Beta, Dev}
 		}
+		return nil, catalog.noMatchingReleaseError(platform, architecture, channels, query)
 	}
+	return &release.Release, nil
+}
 
-	return nil, nil
+// ReleaseFilter narrows down the releases listed by FindReleasesFor, zero values mean no filtering.
+type ReleaseFilter struct {
+	// Channels to list the releases of, in the order of preference, defaults to stable, beta and dev.
+	Channels []Channel
+	// ReleasedFrom and ReleasedUntil bound the release date (inclusive).
+	ReleasedFrom  time.Time
+	ReleasedUntil time.Time
 }
 
-func findLatestReleaseFor(releases []Release, query SDKQuery) (*Release, error) {
-	var sortErr error
-	// sorted in descending version order
-	sort.Slice(releases, func(i, j int) bool {
-		releaseI := releases[i]
-		releaseJ := releases[j]
+func (filter ReleaseFilter) channels() []Channel {
+	if len(filter.Channels) > 0 {
+		return filter.Channels
+	}
+	return []Channel{Stable, Beta, Dev}
+}
 
-		releaseVersionI, err := semver.NewVersion(releaseI.Version)
-		if err != nil {
-			sortErr = err
-			return false
-		}
+func (filter ReleaseFilter) includes(release Release) bool {
+	if !filter.ReleasedFrom.IsZero() && release.ReleaseDate.Before(filter.ReleasedFrom) {
+		return false
+	}
+	if !filter.ReleasedUntil.IsZero() && release.ReleaseDate.After(filter.ReleasedUntil) {
+		return false
+	}
+	return true
+}
 
-		releaseVersionJ, err := semver.NewVersion(releaseJ.Version)
-		if err != nil {
-			sortErr = err
-			return false
-		}
+func (f SDKVersionFinder) FindReleasesFor(platform Platform, architecture Architecture, query SDKQuery, filter ReleaseFilter) ([]Release, error) {
+	return f.FindReleasesForContext(context.Background(), platform, architecture, query, filter)
+}
 
-		return releaseVersionJ.LessThan(releaseVersionI)
-	})
-	if sortErr != nil {
-		return nil, sortErr
+// FindReleasesForContext returns every release matching the query and the filter in descending version order.
+// Releases with the same version are ordered by the filter's channel order.
+func (f SDKVersionFinder) FindReleasesForContext(ctx context.Context, platform Platform, architecture Architecture, query SDKQuery, filter ReleaseFilter) ([]Release, error) {
+	catalog, err := f.ReleaseCatalog(ctx, platform, architecture)
+	if err != nil {
+		return nil, err
 	}
 
-	// Used for parsing the version number from Dart SDK versions like: "2.17.0 (build 2.17.0-266.1.beta)"
-	dartSDKWithBuildVersionExp := regexp.MustCompile(`(.+) \(build (.+)\)`)
+	var releases []Release
+	for _, release := range catalog.Find(query, filter) {
+		releases = append(releases, release.Release)
+	}
+	return releases, nil
+}
 
-	for _, release := range releases {
-		releaseFlutterVersion, err := semver.NewVersion(release.Version)
-		if err != nil {
-			return nil, err
-		}
+func (f SDKVersionFinder) FindReleaseByHash(platform Platform, architecture Architecture, hash string) (*Release, error) {
+	return f.FindReleaseByHashContext(context.Background(), platform, architecture, hash)
+}
+
+// FindReleaseByHashContext returns the release built from the given framework commit, like the revision in a project's .metadata file.
+// Returns nil if none of the channels has a release with the given hash.
+func (f SDKVersionFinder) FindReleaseByHashContext(ctx context.Context, platform Platform, architecture Architecture, hash string) (*Release, error) {
+	if hash == "" {
+		return nil, nil
+	}
+
+	catalog, err := f.ReleaseCatalog(ctx, platform, architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := catalog.ByHash(hash)
+	if len(releases) == 0 {
+		return nil, nil
+	}
+	return &releases[0].Release, nil
+}
+
+func (f SDKVersionFinder) FindChannelHead(platform Platform, architecture Architecture, channel Channel) (*Release, error) {
+	return f.FindChannelHeadContext(context.Background(), platform, architecture, channel)
+}
+
+// FindChannelHeadContext returns the channel's current release.
+// Returns nil if the channel has no current release, like the master channel, which is not published to the releases manifest.
+func (f SDKVersionFinder) FindChannelHeadContext(ctx context.Context, platform Platform, architecture Architecture, channel Channel) (*Release, error) {
+	catalog, err := f.ReleaseCatalog(ctx, platform, architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	release := catalog.ChannelHead(channel)
+	if release == nil {
+		return nil, nil
+	}
+	return &release.Release, nil
+}
 
-		dartSDKVersion := release.DartSdkVersion
-		matches := dartSDKWithBuildVersionExp.FindStringSubmatch(dartSDKVersion)
-		if len(matches) == 3 {
-			dartSDKVersion = matches[1]
+// ArchitecturesFor returns the architectures the given release (identified by its channel, version and hash) is available for on the platform.
+func (f SDKVersionFinder) ArchitecturesFor(ctx context.Context, platform Platform, release Release) ([]Architecture, error) {
+	if lister, ok := f.SDKVersionLister.(allReleasesLister); ok {
+		allReleasesResp, err := lister.listAllReleases(ctx, platform)
+		if err == nil {
+			available := map[Architecture]bool{}
+			for _, r := range allReleasesResp.Releases {
+				if r.Channel == release.Channel && r.Version == release.Version && r.Hash == release.Hash {
+					available[r.Architecture()] = true
+				}
+			}
+			return filterArchitectures(available), nil
+		}
+		if !errors.Is(err, errAllReleasesNotSupported) {
+			return nil, err
 		}
+	}
 
-		releaseDartVersion, err := semver.NewVersion(dartSDKVersion)
+	// The lister can only list a single architecture's releases.
+	available := map[Architecture]bool{}
+	for _, architecture := range architectures {
+		releasesByChannel, err := f.SDKVersionLister.ListReleasesByChannel(ctx, platform, architecture)
 		if err != nil {
 			return nil, err
 		}
 
-		flutterVersionMatch := false
-		dartVersionMatch := false
-
-		if query.FlutterVersion != nil {
-			flutterVersionMatch = query.FlutterVersion.Equal(releaseFlutterVersion)
-		} else if query.FlutterVersionConstraint != nil {
-			flutterVersionMatch = query.FlutterVersionConstraint.Check(releaseFlutterVersion)
-		} else {
-			flutterVersionMatch = true
+		for _, r := range releasesByChannel[release.Channel] {
+			if r.Version == release.Version && r.Hash == release.Hash {
+				available[architecture] = true
+				break
+			}
 		}
+	}
+
+	return filterArchitectures(available), nil
+}
 
-		if query.DartVersion != nil {
-			dartVersionMatch = query.DartVersion.Equal(releaseDartVersion)
-		} else if query.DartVersionConstraint != nil {
-			dartVersionMatch = query.DartVersionConstraint.Check(releaseDartVersion)
-		} else {
-			dartVersionMatch = true
+func filterArchitectures(available map[Architecture]bool) []Architecture {
+	var filtered []Architecture
+	for _, architecture := range architectures {
+		if available[architecture] {
+			filtered = append(filtered, architecture)
 		}
+	}
+	return filtered
+}
 
-		if flutterVersionMatch && dartVersionMatch {
-			return &release, nil
+// Matches tells whether the release's Flutter and Dart SDK versions satisfy the query.
+// Releases without a Dart SDK version (the oldest ones) only match queries without Dart SDK requirements.
+func (q SDKQuery) Matches(release Release) (bool, error) {
+	releaseFlutterVersion, err := semver.NewVersion(release.Version)
+	if err != nil {
+		return false, err
+	}
+
+	var releaseDartVersion *semver.Version
+	if release.DartSdkVersion != "" {
+		releaseDartVersion, err = ParseDartSDKVersion(release.DartSdkVersion)
+		if err != nil {
+			return false, err
 		}
 	}
 
-	return nil, nil
+	return q.matchesVersions(releaseFlutterVersion, releaseDartVersion), nil
+}
+
+func (q SDKQuery) matchesVersions(flutterVersion, dartVersion *semver.Version) bool {
+	flutterVersionMatch := false
+	if q.FlutterVersion != nil {
+		flutterVersionMatch = q.FlutterVersion.Equal(flutterVersion)
+	} else if q.FlutterVersionConstraint != nil {
+		flutterVersionMatch = q.FlutterVersionConstraint.Check(flutterVersion)
+	} else {
+		flutterVersionMatch = true
+	}
+
+	if q.DartVersion == nil && q.DartVersionConstraint == nil {
+		return flutterVersionMatch
+	}
+	if dartVersion == nil {
+		return false
+	}
+
+	dartVersionMatch := false
+	if q.DartVersion != nil {
+		dartVersionMatch = q.DartVersion.Equal(dartVersion)
+	} else {
+		dartVersionMatch = q.DartVersionConstraint.Check(dartVersion)
+	}
+
+	return flutterVersionMatch && dartVersionMatch
+}
+
+// Used for parsing the version number from Dart SDK versions like: "2.17.0 (build 2.17.0-266.1.beta)"
+var dartSDKWithBuildVersionExp = regexp.MustCompile(`(.+) \(build (.+)\)`)
+
+// ParseDartSDKVersion parses a release's dart_sdk_version, which might contain the build version too.
+func ParseDartSDKVersion(dartSDKVersion string) (*semver.Version, error) {
+	matches := dartSDKWithBuildVersionExp.FindStringSubmatch(dartSDKVersion)
+	if len(matches) == 3 {
+		dartSDKVersion = matches[1]
+	}
+
+	return semver.NewVersion(dartSDKVersion)
 }
 
 type SDKVersionLister interface {
-	ListReleasesByChannel(platform Platform, architecture Architecture) (map[string][]Release, error)
+	ListReleasesByChannel(ctx context.Context, platform Platform, architecture Architecture) (map[string][]Release, error)
+}
+
+// UnexpectedResponseError is returned when the releases manifest request doesn't respond with a JSON document,
+// for example with a non-2xx status code or with an HTML error page.
+type UnexpectedResponseError struct {
+	URL         string
+	StatusCode  int
+	ContentType string
+}
+
+func (e UnexpectedResponseError) Error() string {
+	if e.StatusCode < 200 || e.StatusCode > 299 {
+		return fmt.Sprintf("unexpected response from %s: status code %d", e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected response from %s: content type %s", e.URL, e.ContentType)
+}
+
+type SDKVersionListerOption func(*defaultSDKVersionLister)
+
+// WithHTTPClient sets the HTTP client used for downloading the releases manifest, defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) SDKVersionListerOption {
+	return func(l *defaultSDKVersionLister) {
+		l.client = client
+	}
+}
+
+// WithRequestTimeout limits the duration of a single releases manifest request, zero means no limit.
+func WithRequestTimeout(timeout time.Duration) SDKVersionListerOption {
+	return func(l *defaultSDKVersionLister) {
+		l.requestTimeout = timeout
+	}
 }
 
 type defaultSDKVersionLister struct {
-	baseURLFormat string
+	storageBaseURLs []string
+	client          *http.Client
+	requestTimeout  time.Duration
 }
 
-func NewSDKVersionLister() SDKVersionLister {
-	return defaultSDKVersionLister{baseURLFormat: flutterInfraReleasesURLFormat}
+func NewSDKVersionLister(opts ...SDKVersionListerOption) SDKVersionLister {
+	return newDefaultSDKVersionLister(opts...)
 }
 
-const flutterInfraReleasesURLFormat = "https://storage.googleapis.com/flutter_infra_release/releases/releases_%s.json"
+func newDefaultSDKVersionLister(opts ...SDKVersionListerOption) defaultSDKVersionLister {
+	l := defaultSDKVersionLister{
+		storageBaseURLs: defaultStorageBaseURLs(),
+		client:          http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&l)
+	}
+	return l
+}
 
-func (l defaultSDKVersionLister) ListReleasesByChannel(platform Platform, architecture Architecture) (map[string][]Release, error) {
-	allReleasesResp, err := l.listAllReleases(platform)
+func (l defaultSDKVersionLister) ListReleasesByChannel(ctx context.Context, platform Platform, architecture Architecture) (map[string][]Release, error) {
+	allReleasesResp, err := l.listAllReleases(ctx, platform)
 	if err != nil {
 		return nil, err
 	}
 
+	return groupReleasesByChannel(*allReleasesResp, architecture), nil
+}
+
+func groupReleasesByChannel(allReleasesResp ReleasesResp, architecture Architecture) map[string][]Release {
 	releasesByChannel := map[string][]Release{}
 
+	for _, release := range architectureReleases(allReleasesResp, architecture) {
+		releases := releasesByChannel[release.Channel]
+		releases = append(releases, release)
+		releasesByChannel[release.Channel] = releases
+	}
+
+	return releasesByChannel
+}
+
+// architectureReleases returns the manifest's releases for the given architecture, with their BaseURL and Current fields set.
+func architectureReleases(allReleasesResp ReleasesResp, architecture Architecture) []Release {
+	var releases []Release
 	for _, release := range allReleasesResp.Releases {
-		if platform == MacOS && release.DartSdkArch != string(architecture) {
+		if release.Architecture() != architecture {
 			continue
 		}
 
-		releases := releasesByChannel[release.Channel]
+		release.BaseURL = allReleasesResp.BaseURL
+		release.Current = release.Hash != "" && release.Hash == allReleasesResp.currentReleaseOf(release.Channel)
 		releases = append(releases, release)
-		releasesByChannel[release.Channel] = releases
 	}
+	return releases
+}
 
-	return releasesByChannel, nil
+// allReleasesLister is implemented by listers, which can list every release of the platform's manifest at once.
+type allReleasesLister interface {
+	listAllReleases(ctx context.Context, platform Platform) (*ReleasesResp, error)
 }
 
-func (l defaultSDKVersionLister) listAllReleases(platform Platform) (*ReleasesResp, error) {
-	flutterReleaseURL := fmt.Sprintf(l.baseURLFormat, platform)
-	client := http.DefaultClient
-	resp, err := client.Get(flutterReleaseURL)
+var errAllReleasesNotSupported = errors.New("listing every release of a platform is not supported")
+
+func (l defaultSDKVersionLister) listAllReleases(ctx context.Context, platform Platform) (*ReleasesResp, error) {
+	manifest, err := l.fetchReleasesManifest(ctx, platform, manifestMetadata{})
 	if err != nil {
 		return nil, err
 	}
-	var releases ReleasesResp
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&releases); err != nil {
+
+	return parseReleasesManifest(manifest)
+}
+
+// manifestMetadata describes where a releases manifest was downloaded from and its HTTP cache validators.
+type manifestMetadata struct {
+	URL            string `json:"url"`
+	StorageBaseURL string `json:"storage_base_url"`
+	ETag           string `json:"etag,omitempty"`
+	LastModified   string `json:"last_modified,omitempty"`
+}
+
+type releasesManifest struct {
+	Body        []byte
+	Metadata    manifestMetadata
+	NotModified bool
+}
+
+// fetchReleasesManifest downloads the releases manifest of the given platform from the first storage which responds successfully,
+// the request is conditional if the metadata of an earlier download from the same URL is provided.
+func (l defaultSDKVersionLister) fetchReleasesManifest(ctx context.Context, platform Platform, metadata manifestMetadata) (*releasesManifest, error) {
+	var errs []error
+	for _, storageBaseURL := range l.storageBaseURLs {
+		manifest, err := l.fetchReleasesManifestFrom(ctx, storageBaseURL, platform, metadata)
+		if err == nil {
+			return manifest, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no storage base url provided")
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (l defaultSDKVersionLister) fetchReleasesManifestFrom(ctx context.Context, storageBaseURL string, platform Platform, metadata manifestMetadata) (*releasesManifest, error) {
+	if l.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.requestTimeout)
+		defer cancel()
+	}
+
+	flutterReleaseURL := releasesManifestURL(storageBaseURL, platform)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, flutterReleaseURL, nil)
+	if err != nil {
 		return nil, err
 	}
+	if metadata.URL == flutterReleaseURL {
+		if metadata.ETag != "" {
+			req.Header.Set("If-None-Match", metadata.ETag)
+		}
+		if metadata.LastModified != "" {
+			req.Header.Set("If-Modified-Since", metadata.LastModified)
+		}
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotModified && metadata.URL == flutterReleaseURL {
+		return &releasesManifest{Metadata: metadata, NotModified: true}, nil
+	}
+
+	if err := checkReleasesResponse(flutterReleaseURL, resp); err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download releases manifest (%s): %w", flutterReleaseURL, err)
+	}
+
+	return &releasesManifest{
+		Body: body,
+		Metadata: manifestMetadata{
+			URL:            flutterReleaseURL,
+			StorageBaseURL: storageBaseURL,
+			ETag:           resp.Header.Get("ETag"),
+			LastModified:   resp.Header.Get("Last-Modified"),
+		},
+	}, nil
+}
+
+// parseReleasesManifest parses the manifest and points its base url to the storage the manifest was downloaded from.
+func parseReleasesManifest(manifest *releasesManifest) (*ReleasesResp, error) {
+	var releases ReleasesResp
+	if err := json.Unmarshal(manifest.Body, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases manifest (%s): %w", manifest.Metadata.URL, err)
+	}
+
+	if manifest.Metadata.StorageBaseURL != "" {
+		releases.BaseURL = rewriteStorageBaseURL(releases.BaseURL, manifest.Metadata.StorageBaseURL)
+	}
 
 	return &releases, nil
 }
+
+func checkReleasesResponse(url string, resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return UnexpectedResponseError{URL: url, StatusCode: resp.StatusCode, ContentType: contentType}
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == "text/html" {
+		return UnexpectedResponseError{URL: url, StatusCode: resp.StatusCode, ContentType: contentType}
+	}
+
+	return nil
+}
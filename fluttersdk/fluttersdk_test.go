@@ -1,10 +1,14 @@
 package fluttersdk
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,8 +50,10 @@ func TestSDKVersionFinder_FindLatestReleaseFor(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			lister := newDefaultSDKVersionLister()
+			lister.storageBaseURLs = []string{ts.URL}
 			f := SDKVersionFinder{
-				SDKVersionLister: defaultSDKVersionLister{baseURLFormat: ts.URL + "/releases_%s.json"},
+				SDKVersionLister: lister,
 			}
 			got, err := f.FindLatestReleaseFor(tt.platform, tt.architecture, tt.channel, tt.query)
 			require.NoError(t, err)
@@ -57,6 +63,334 @@ func TestSDKVersionFinder_FindLatestReleaseFor(t *testing.T) {
 	}
 }
 
+func TestSDKVersionLister_ListReleasesByChannel(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		opts        []SDKVersionListerOption
+		wantErr     string
+		wantRespErr *UnexpectedResponseError
+	}{
+		{
+			name: "Non-2xx status code",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			wantRespErr: &UnexpectedResponseError{StatusCode: http.StatusNotFound},
+		},
+		{
+			name: "HTML error page",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				_, _ = w.Write([]byte("<html><body>Proxy error</body></html>"))
+			},
+			wantRespErr: &UnexpectedResponseError{StatusCode: http.StatusOK, ContentType: "text/html; charset=utf-8"},
+		},
+		{
+			name: "Request timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			opts:    []SDKVersionListerOption{WithRequestTimeout(10 * time.Millisecond)},
+			wantErr: "context deadline exceeded",
+		},
+		{
+			name: "Custom HTTP client",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+				_, _ = w.Write([]byte(flutterSDKsResponse))
+			},
+			opts: []SDKVersionListerOption{WithHTTPClient(&http.Client{Transport: userAgentTransport{agent: "test-agent"}})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(tt.handler)
+			defer ts.Close()
+
+			lister := newDefaultSDKVersionLister(tt.opts...)
+			lister.storageBaseURLs = []string{ts.URL}
+
+			got, err := lister.ListReleasesByChannel(context.Background(), MacOS, ARM64)
+			switch {
+			case tt.wantRespErr != nil:
+				var respErr UnexpectedResponseError
+				require.True(t, errors.As(err, &respErr))
+				require.Equal(t, tt.wantRespErr.StatusCode, respErr.StatusCode)
+				require.Equal(t, tt.wantRespErr.ContentType, respErr.ContentType)
+				require.Equal(t, ts.URL+"/flutter_infra_release/releases/releases_macos.json", respErr.URL)
+			case tt.wantErr != "":
+				require.ErrorContains(t, err, tt.wantErr)
+			default:
+				require.NoError(t, err)
+				require.Len(t, got["stable"], 1)
+			}
+		})
+	}
+}
+
+func TestSDKVersionLister_ListReleasesByChannel_Architecture(t *testing.T) {
+	tests := []struct {
+		name         string
+		architecture Architecture
+		wantVersions []string
+	}{
+		{
+			name:         "x64 includes releases without dart_sdk_arch",
+			architecture: X64,
+			wantVersions: []string{"3.22.2", "3.0.0"},
+		},
+		{
+			name:         "arm64",
+			architecture: ARM64,
+			wantVersions: []string{"3.22.2"},
+		},
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(linuxFlutterSDKsResponse))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lister := NewSDKVersionLister(WithStorageBaseURLs(ts.URL))
+			got, err := lister.ListReleasesByChannel(context.Background(), Linux, tt.architecture)
+			require.NoError(t, err)
+
+			var gotVersions []string
+			for _, release := range got["stable"] {
+				gotVersions = append(gotVersions, release.Version)
+			}
+			require.Equal(t, tt.wantVersions, gotVersions)
+		})
+	}
+}
+
+type staticSDKVersionLister map[string][]Release
+
+func (l staticSDKVersionLister) ListReleasesByChannel(ctx context.Context, platform Platform, architecture Architecture) (map[string][]Release, error) {
+	return l, nil
+}
+
+func TestSDKVersionFinder_FindReleasesFor(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return d
+	}
+
+	lister := staticSDKVersionLister{
+		"stable": {
+			{Channel: "stable", Version: "3.13.8", DartSdkVersion: "3.1.4", ReleaseDate: date("2023-10-18")},
+			{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5", ReleaseDate: date("2023-10-25")},
+			{Channel: "stable", Version: "3.10.6", DartSdkVersion: "3.0.6", ReleaseDate: date("2023-07-13")},
+			{Channel: "stable", Version: "v1.0.0", ReleaseDate: date("2018-12-04")},
+		},
+		"beta": {
+			{Channel: "beta", Version: "3.13.9", DartSdkVersion: "3.1.5", ReleaseDate: date("2023-10-25")},
+			{Channel: "beta", Version: "3.16.0-0.5.pre", DartSdkVersion: "3.2.0 (build 3.2.0-210.4.beta)", ReleaseDate: date("2023-10-24")},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		query        SDKQuery
+		filter       ReleaseFilter
+		wantReleases []string
+	}{
+		{
+			name:         "All releases",
+			wantReleases: []string{"3.16.0-0.5.pre@beta", "3.13.9@stable", "3.13.9@beta", "3.13.8@stable", "3.10.6@stable", "v1.0.0@stable"},
+		},
+		{
+			name:         "Flutter version constraint",
+			query:        SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.13.0 <4.0.0")},
+			filter:       ReleaseFilter{Channels: []Channel{Stable}},
+			wantReleases: []string{"3.13.9@stable", "3.13.8@stable"},
+		},
+		{
+			name:         "Dart version constraint skips releases without Dart version",
+			query:        SDKQuery{DartVersionConstraint: mustNewConstraint(t, "<3.1.5")},
+			wantReleases: []string{"3.13.8@stable", "3.10.6@stable"},
+		},
+		{
+			name:         "Channel preference",
+			query:        SDKQuery{FlutterVersion: semver.MustParse("3.13.9")},
+			filter:       ReleaseFilter{Channels: []Channel{Beta, Stable}},
+			wantReleases: []string{"3.13.9@beta", "3.13.9@stable"},
+		},
+		{
+			name:         "Release date range",
+			filter:       ReleaseFilter{ReleasedFrom: date("2023-07-13"), ReleasedUntil: date("2023-10-24")},
+			wantReleases: []string{"3.16.0-0.5.pre@beta", "3.13.8@stable", "3.10.6@stable"},
+		},
+		{
+			name:   "No matching release",
+			query:  SDKQuery{FlutterVersion: semver.MustParse("3.13.7")},
+			filter: ReleaseFilter{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := SDKVersionFinder{SDKVersionLister: lister}
+
+			got, err := f.FindReleasesFor(MacOS, ARM64, tt.query, tt.filter)
+			require.NoError(t, err)
+
+			var gotReleases []string
+			for _, release := range got {
+				gotReleases = append(gotReleases, release.Version+"@"+release.Channel)
+			}
+			require.Equal(t, tt.wantReleases, gotReleases)
+		})
+	}
+}
+
+func mustNewConstraint(t *testing.T, constraint string) *semver.Constraints {
+	c, err := semver.NewConstraint(constraint)
+	require.NoError(t, err)
+	return c
+}
+
+func TestSDKVersionFinder_ArchitecturesFor(t *testing.T) {
+	requestCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		_, err := w.Write([]byte(linuxFlutterSDKsResponse))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	f := SDKVersionFinder{SDKVersionLister: NewSDKVersionLister(WithStorageBaseURLs(ts.URL))}
+
+	got, err := f.ArchitecturesFor(context.Background(), Linux, Release{Channel: "stable", Version: "3.22.2", Hash: "761747bfc538b5af34aa0d3fac380f1bc331ec49"})
+	require.NoError(t, err)
+	require.Equal(t, []Architecture{X64, ARM64}, got)
+
+	require.Equal(t, 1, requestCount)
+
+	got, err = f.ArchitecturesFor(context.Background(), Linux, Release{Channel: "stable", Version: "3.0.0", Hash: "ee4e09cce01d6f2d7f4baebd247fde02e5008851"})
+	require.NoError(t, err)
+	require.Equal(t, []Architecture{X64}, got)
+
+	cached := SDKVersionFinder{SDKVersionLister: NewCachingSDKVersionLister(NewSDKVersionLister(WithStorageBaseURLs(ts.URL)), t.TempDir())}
+	got, err = cached.ArchitecturesFor(context.Background(), Linux, Release{Channel: "stable", Version: "3.22.2", Hash: "761747bfc538b5af34aa0d3fac380f1bc331ec49"})
+	require.NoError(t, err)
+	require.Equal(t, []Architecture{X64, ARM64}, got)
+	require.Equal(t, 3, requestCount)
+
+	static := SDKVersionFinder{SDKVersionLister: staticSDKVersionLister{"stable": {{Channel: "stable", Version: "3.22.2", Hash: "761747bfc538b5af34aa0d3fac380f1bc331ec49"}}}}
+	got, err = static.ArchitecturesFor(context.Background(), Linux, Release{Channel: "stable", Version: "3.22.2", Hash: "761747bfc538b5af34aa0d3fac380f1bc331ec49"})
+	require.NoError(t, err)
+	require.Equal(t, []Architecture{X64, ARM64}, got)
+}
+
+func TestSDKVersionFinder_FindReleaseByHash(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(flutterSDKsResponse))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name        string
+		hash        string
+		wantVersion string
+		wantChannel string
+	}{
+		{
+			name:        "Stable release",
+			hash:        "d211f42860350d914a5ad8102f9ec32764dc6d06",
+			wantVersion: "3.13.9",
+			wantChannel: "stable",
+		},
+		{
+			name:        "Dev release",
+			hash:        "13A2FB10B838971CE211230F8FFDD094C14AF02C",
+			wantVersion: "2.13.0-0.1.pre",
+			wantChannel: "dev",
+		},
+		{
+			name: "Unknown hash",
+			hash: "0000000000000000000000000000000000000000",
+		},
+		{
+			name: "Empty hash",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := SDKVersionFinder{SDKVersionLister: NewSDKVersionLister(WithStorageBaseURLs(ts.URL))}
+
+			got, err := f.FindReleaseByHash(MacOS, ARM64, tt.hash)
+			require.NoError(t, err)
+			if tt.wantVersion == "" {
+				require.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			require.Equal(t, tt.wantVersion, got.Version)
+			require.Equal(t, tt.wantChannel, got.Channel)
+		})
+	}
+}
+
+func TestSDKVersionFinder_FindChannelHead(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(flutterSDKsResponse))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name        string
+		channel     Channel
+		wantVersion string
+	}{
+		{
+			name:        "Stable channel",
+			channel:     Stable,
+			wantVersion: "3.13.9",
+		},
+		{
+			name:        "Dev channel",
+			channel:     Dev,
+			wantVersion: "2.13.0-0.1.pre",
+		},
+		{
+			name:    "Channel without current release",
+			channel: "master",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := SDKVersionFinder{SDKVersionLister: NewSDKVersionLister(WithStorageBaseURLs(ts.URL))}
+
+			got, err := f.FindChannelHead(MacOS, ARM64, tt.channel)
+			require.NoError(t, err)
+			if tt.wantVersion == "" {
+				require.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			require.Equal(t, tt.wantVersion, got.Version)
+			require.Equal(t, string(tt.channel), got.Channel)
+			require.True(t, got.Current)
+		})
+	}
+}
+
+type userAgentTransport struct {
+	agent string
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", t.agent)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
 const flutterSDKsResponse = `{
 	"base_url": "https://storage.googleapis.com/flutter_infra_release/releases",
 	"current_release": {
@@ -107,3 +441,167 @@ const flutterSDKsResponse = `{
 		}
 	]
 }`
+
+const linuxFlutterSDKsResponse = `{
+	"base_url": "https://storage.googleapis.com/flutter_infra_release/releases",
+	"current_release": {
+		"beta": "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+		"dev": "13a2fb10b838971ce211230f8ffdd094c14af02c",
+		"stable": "761747bfc538b5af34aa0d3fac380f1bc331ec49"
+	},
+	"releases": [
+		{
+			"hash": "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+			"channel": "stable",
+			"version": "3.22.2",
+			"dart_sdk_version": "3.4.3",
+			"dart_sdk_arch": "x64",
+			"release_date": "2024-06-06T17:35:58.429186Z",
+			"archive": "stable/linux/flutter_linux_3.22.2-stable.tar.xz",
+			"sha256": "ac4e1e3b8bd4c4dca1a1d6b0f1c3e6a9a0a0d5c2b6a0d43c4a9a0bcbfd4bf70e"
+		},
+		{
+			"hash": "761747bfc538b5af34aa0d3fac380f1bc331ec49",
+			"channel": "stable",
+			"version": "3.22.2",
+			"dart_sdk_version": "3.4.3",
+			"dart_sdk_arch": "arm64",
+			"release_date": "2024-06-06T17:35:58.429186Z",
+			"archive": "stable/linux/flutter_linux_arm64_3.22.2-stable.tar.xz",
+			"sha256": "0b6d2c5e8a0f0dcd6a4a5b3b0de3d6dd64a0f4b3c7e2f5b6e4d0e7a8c9b0a1f2"
+		},
+		{
+			"hash": "ee4e09cce01d6f2d7f4baebd247fde02e5008851",
+			"channel": "stable",
+			"version": "3.0.0",
+			"dart_sdk_version": "2.17.0",
+			"release_date": "2022-05-11T19:17:28.372866Z",
+			"archive": "stable/linux/flutter_linux_3.0.0-stable.tar.xz",
+			"sha256": "3f2d7fb0e6d84d7e0dd12cc00f6f3e4d39f4f1a7d8d5f2c0a2e6f5f0f0a1b2c3"
+		}
+	]
+}`
+
+func TestSDKVersionFinder_FindLatestReleaseFor_NoMatchingRelease(t *testing.T) {
+	lister := staticSDKVersionLister{
+		"stable": {
+			{Channel: "stable", Version: "3.22.3", DartSdkVersion: "3.4.4"},
+			{Channel: "stable", Version: "3.22.2", DartSdkVersion: "3.4.3"},
+			{Channel: "stable", Version: "3.19.6", DartSdkVersion: "3.3.4"},
+		},
+		"beta": {
+			{Channel: "beta", Version: "3.23.0-0.1.pre", DartSdkVersion: "3.5.0 (build 3.5.0-180.3.beta)"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		channel   Channel
+		query     SDKQuery
+		wantErr   string
+		wantBelow string
+		wantAbove string
+	}{
+		{
+			name:      "Unreleased Flutter version",
+			query:     SDKQuery{FlutterVersion: semver.MustParse("3.22.4")},
+			wantErr:   "no Flutter SDK release matches Flutter 3.22.4 on macos arm64 (channels: stable, beta, dev), did you mean 3.22.3 (stable, Dart 3.4.4) or 3.23.0-0.1.pre (beta, Dart 3.5.0 (build 3.5.0-180.3.beta))?",
+			wantBelow: "3.22.3",
+			wantAbove: "3.23.0-0.1.pre",
+		},
+		{
+			name:      "Flutter version constraint on a channel",
+			channel:   Stable,
+			query:     SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.20.0 <3.22.0")},
+			wantErr:   "no Flutter SDK release matches Flutter >=3.20.0 <3.22.0 on macos arm64 (channels: stable), did you mean 3.19.6 (stable, Dart 3.3.4) or 3.22.2 (stable, Dart 3.4.3)?",
+			wantBelow: "3.19.6",
+			wantAbove: "3.22.2",
+		},
+		{
+			name:      "Dart version without a newer release",
+			query:     SDKQuery{DartVersion: semver.MustParse("3.6.0")},
+			wantErr:   "no Flutter SDK release matches Dart 3.6.0 on macos arm64 (channels: stable, beta, dev), did you mean 3.23.0-0.1.pre (beta, Dart 3.5.0 (build 3.5.0-180.3.beta))?",
+			wantBelow: "3.23.0-0.1.pre",
+		},
+		{
+			name:      "Dart version of the matching Flutter versions",
+			query:     SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.22.0"), DartVersion: semver.MustParse("3.6.0")},
+			wantErr:   "no Flutter SDK release matches Flutter >=3.22.0 and Dart 3.6.0 on macos arm64 (channels: stable, beta, dev), did you mean 3.23.0-0.1.pre (beta, Dart 3.5.0 (build 3.5.0-180.3.beta))?",
+			wantBelow: "3.23.0-0.1.pre",
+		},
+		{
+			name:      "Flutter version constraint with a Dart requirement",
+			channel:   Stable,
+			query:     SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, "<3.22.0 >=3.20.0"), DartVersionConstraint: mustNewConstraint(t, ">=3.0.0")},
+			wantErr:   "no Flutter SDK release matches Flutter <3.22.0 >=3.20.0 and Dart >=3.0.0 on macos arm64 (channels: stable), did you mean 3.19.6 (stable, Dart 3.3.4) or 3.22.2 (stable, Dart 3.4.3)?",
+			wantBelow: "3.19.6",
+			wantAbove: "3.22.2",
+		},
+		{
+			name:    "Unknown channel",
+			channel: "master",
+			wantErr: "no Flutter SDK release matches any version on macos arm64 (channels: master)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := SDKVersionFinder{SDKVersionLister: lister}
+			got, err := f.FindLatestReleaseFor(MacOS, ARM64, tt.channel, tt.query)
+			require.Nil(t, got)
+			require.EqualError(t, err, tt.wantErr)
+
+			var noMatchingReleaseErr NoMatchingReleaseError
+			require.True(t, errors.As(err, &noMatchingReleaseErr))
+			if tt.wantBelow == "" {
+				require.Nil(t, noMatchingReleaseErr.Below)
+			} else {
+				require.Equal(t, tt.wantBelow, noMatchingReleaseErr.Below.Version)
+			}
+			if tt.wantAbove == "" {
+				require.Nil(t, noMatchingReleaseErr.Above)
+			} else {
+				require.Equal(t, tt.wantAbove, noMatchingReleaseErr.Above.Version)
+			}
+		})
+	}
+}
+
+type minVersionTestConstraint struct {
+	min *semver.Version
+}
+
+func (c minVersionTestConstraint) Check(version *semver.Version) bool {
+	return !version.LessThan(c.min)
+}
+
+func (c minVersionTestConstraint) String() string {
+	return "<4.0.0 >=" + c.min.String()
+}
+
+func Test_requestedVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    *semver.Version
+		constraint VersionConstraint
+		want       string
+	}{
+		{name: "Exact version", version: semver.MustParse("3.22.2"), want: "3.22.2"},
+		{name: "Constraint with its lowest bound", constraint: minVersionTestConstraint{min: semver.MustParse("3.19.0")}, want: "3.19.0"},
+		{name: "Upper bound first", constraint: mustNewConstraint(t, "<3.0.0 >=2.10.0"), want: "2.10.0"},
+		{name: "Caret", constraint: mustNewConstraint(t, "^3.22.0"), want: "3.22.0"},
+		{name: "Lowest bound of the groups", constraint: mustNewConstraint(t, ">=3.0.0 <4.0.0 || >=2.10.0 <2.11.0"), want: "2.10.0"},
+		{name: "Hyphen range", constraint: mustNewConstraint(t, "2.10 - 3.0"), want: "2.10.0"},
+		{name: "Upper bound only", constraint: mustNewConstraint(t, "<3.0.0")},
+		{name: "No requirement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requestedVersion(tt.version, tt.constraint)
+			if tt.want == "" {
+				require.Nil(t, got)
+				return
+			}
+			require.Equal(t, tt.want, got.String())
+		})
+	}
+}
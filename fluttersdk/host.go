@@ -0,0 +1,37 @@
+package fluttersdk
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// HostPlatformAndArchitecture returns the Flutter platform and architecture of the machine the code runs on.
+func HostPlatformAndArchitecture() (Platform, Architecture, error) {
+	return platformAndArchitectureFor(runtime.GOOS, runtime.GOARCH)
+}
+
+func platformAndArchitectureFor(goos, goarch string) (Platform, Architecture, error) {
+	var platform Platform
+	switch goos {
+	case "darwin":
+		platform = MacOS
+	case "linux":
+		platform = Linux
+	case "windows":
+		platform = Windows
+	default:
+		return "", "", fmt.Errorf("unsupported operating system: %s", goos)
+	}
+
+	var architecture Architecture
+	switch goarch {
+	case "amd64":
+		architecture = X64
+	case "arm64":
+		architecture = ARM64
+	default:
+		return "", "", fmt.Errorf("unsupported architecture: %s", goarch)
+	}
+
+	return platform, architecture, nil
+}
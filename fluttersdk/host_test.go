@@ -0,0 +1,64 @@
+package fluttersdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_platformAndArchitectureFor(t *testing.T) {
+	tests := []struct {
+		name             string
+		goos             string
+		goarch           string
+		wantPlatform     Platform
+		wantArchitecture Architecture
+		wantErr          string
+	}{
+		{
+			name:             "macOS Apple Silicon",
+			goos:             "darwin",
+			goarch:           "arm64",
+			wantPlatform:     MacOS,
+			wantArchitecture: ARM64,
+		},
+		{
+			name:             "Linux x64",
+			goos:             "linux",
+			goarch:           "amd64",
+			wantPlatform:     Linux,
+			wantArchitecture: X64,
+		},
+		{
+			name:             "Windows x64",
+			goos:             "windows",
+			goarch:           "amd64",
+			wantPlatform:     Windows,
+			wantArchitecture: X64,
+		},
+		{
+			name:    "Unsupported operating system",
+			goos:    "freebsd",
+			goarch:  "amd64",
+			wantErr: "unsupported operating system: freebsd",
+		},
+		{
+			name:    "Unsupported architecture",
+			goos:    "linux",
+			goarch:  "386",
+			wantErr: "unsupported architecture: 386",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPlatform, gotArchitecture, err := platformAndArchitectureFor(tt.goos, tt.goarch)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantPlatform, gotPlatform)
+				require.Equal(t, tt.wantArchitecture, gotArchitecture)
+			}
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package fluttersdk
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	zipArchiveExt   = ".zip"
+	tarXZArchiveExt = ".tar.xz"
+)
+
+// ChecksumMismatchError is returned when the downloaded archive's SHA-256 doesn't match the one in the releases manifest.
+type ChecksumMismatchError struct {
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected sha256 %s, got %s", e.URL, e.Expected, e.Actual)
+}
+
+/*
+Installer downloads, verifies and unpacks Flutter SDK archives.
+
+The archive is streamed from the release's BaseURL + Archive while its SHA-256 is calculated,
+then it is unpacked into a staging directory next to the target directory,
+which is finally renamed to the target directory.
+This way the target directory either contains a complete SDK or doesn't exist at all.
+*/
+type Installer struct {
+	client          *http.Client
+	storageBaseURLs []string
+}
+
+func NewInstaller(opts ...InstallerOption) Installer {
+	i := Installer{client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&i)
+	}
+	return i
+}
+
+// WithInstallerHTTPClient sets the HTTP client used for downloading the release archives, defaults to http.DefaultClient.
+func WithInstallerHTTPClient(client *http.Client) InstallerOption {
+	return func(i *Installer) {
+		i.client = client
+	}
+}
+
+// Install installs the release into targetDir, the Flutter archives contain a top-level flutter directory,
+// so the SDK's entry point will be at targetDir/flutter/bin/flutter. targetDir must not exist.
+func (i Installer) Install(release Release, targetDir string) error {
+	return i.InstallContext(context.Background(), release, targetDir)
+}
+
+// InstallContext is Install with a context, cancelling the context aborts the archive download.
+func (i Installer) InstallContext(ctx context.Context, release Release, targetDir string) error {
+	archiveURL, err := release.ArchiveURL()
+	if err != nil {
+		return err
+	}
+	if release.Sha256 == "" {
+		return fmt.Errorf("release %s (%s) has no sha256", release.Version, release.Channel)
+	}
+
+	if _, err := os.Stat(targetDir); err == nil {
+		return fmt.Errorf("target directory already exists: %s", targetDir)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	parentDir := filepath.Dir(targetDir)
+	if err := os.MkdirAll(parentDir, 0755); err != nil {
+		return err
+	}
+
+	archiveFile, err := os.CreateTemp(parentDir, ".flutter-archive-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = archiveFile.Close()
+		_ = os.Remove(archiveFile.Name())
+	}()
+
+	archiveURL, err = i.downloadFromFirstAvailable(ctx, i.archiveURLs(archiveURL), release.Sha256, archiveFile)
+	if err != nil {
+		return err
+	}
+
+	stagingDir, err := os.MkdirTemp(parentDir, ".flutter-sdk-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(stagingDir)
+	}()
+
+	if err := extractArchive(archiveFile, release.Archive, stagingDir); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", archiveURL, err)
+	}
+
+	return os.Rename(stagingDir, targetDir)
+}
+
+// ArchiveURL returns the download URL of the release's archive.
+func (r Release) ArchiveURL() (string, error) {
+	if r.BaseURL == "" {
+		return "", fmt.Errorf("release %s (%s) has no base url", r.Version, r.Channel)
+	}
+	if r.Archive == "" {
+		return "", fmt.Errorf("release %s (%s) has no archive", r.Version, r.Channel)
+	}
+	return strings.TrimSuffix(r.BaseURL, "/") + "/" + strings.TrimPrefix(r.Archive, "/"), nil
+}
+
+func (i Installer) archiveURLs(archiveURL string) []string {
+	if len(i.storageBaseURLs) == 0 {
+		return []string{archiveURL}
+	}
+
+	var urls []string
+	for _, storageBaseURL := range i.storageBaseURLs {
+		urls = append(urls, rewriteStorageBaseURL(archiveURL, storageBaseURL))
+	}
+	return urls
+}
+
+// downloadFromFirstAvailable downloads the archive from the first URL which serves it with the expected checksum.
+func (i Installer) downloadFromFirstAvailable(ctx context.Context, archiveURLs []string, expectedSha256 string, dst *os.File) (string, error) {
+	var errs []error
+	for _, archiveURL := range archiveURLs {
+		if err := resetFile(dst); err != nil {
+			return "", err
+		}
+
+		err := i.download(ctx, archiveURL, expectedSha256, dst)
+		if err == nil {
+			return archiveURL, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		errs = append(errs, err)
+	}
+	return "", errors.Join(errs...)
+}
+
+func resetFile(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.Seek(0, io.SeekStart)
+	return err
+}
+
+func (i Installer) download(ctx context.Context, archiveURL, expectedSha256 string, dst *os.File) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, archiveURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to download %s: %s", archiveURL, resp.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(dst, hash), resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %w", archiveURL, err)
+	}
+
+	actualSha256 := hex.EncodeToString(hash.Sum(nil))
+	if !strings.EqualFold(actualSha256, expectedSha256) {
+		return ChecksumMismatchError{URL: archiveURL, Expected: expectedSha256, Actual: actualSha256}
+	}
+
+	return nil
+}
+
+func extractArchive(archiveFile *os.File, archiveName, dstDir string) error {
+	info, err := archiveFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasSuffix(archiveName, zipArchiveExt):
+		return extractZip(archiveFile, info.Size(), dstDir)
+	case strings.HasSuffix(archiveName, tarXZArchiveExt):
+		if _, err := archiveFile.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		return extractTarXZ(archiveFile, dstDir)
+	default:
+		return fmt.Errorf("unsupported archive format: %s", archiveName)
+	}
+}
+
+func extractZip(r io.ReaderAt, size int64, dstDir string) error {
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	w := newArchiveWriter(dstDir)
+	for _, file := range zipReader.File {
+		pth, err := w.entryPath(file.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := file.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(pth, 0755); err != nil {
+				return err
+			}
+		case mode&os.ModeSymlink != 0:
+			target, err := readZipFile(file)
+			if err != nil {
+				return err
+			}
+			if err := w.writeSymlink(file.Name, string(target), pth); err != nil {
+				return err
+			}
+		default:
+			rc, err := file.Open()
+			if err != nil {
+				return err
+			}
+			err = writeFile(rc, pth, mode.Perm())
+			_ = rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	return io.ReadAll(rc)
+}
+
+func extractTarXZ(r io.Reader, dstDir string) error {
+	xzReader, err := xz.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	w := newArchiveWriter(dstDir)
+	tarReader := tar.NewReader(xzReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		pth, err := w.entryPath(header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(pth, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(tarReader, pth, header.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := w.writeSymlink(header.Name, header.Linkname, pth); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			linkTarget, err := w.entryPath(header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+				return err
+			}
+			if err := os.Link(linkTarget, pth); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// archiveEntryPath returns the destination path of an archive entry and rejects entries pointing outside of dstDir.
+func archiveEntryPath(dstDir, name string) (string, error) {
+	pth := filepath.Join(dstDir, name)
+	if !isInsideDir(filepath.Clean(dstDir), pth) {
+		return "", fmt.Errorf("invalid archive entry: %s", name)
+	}
+	return pth, nil
+}
+
+// maxSymlinkDepth is the maximum number of nested symlinks followed when resolving a symlink target.
+const maxSymlinkDepth = 40
+
+/*
+archiveWriter extracts the entries of an archive into dstDir and keeps every entry inside of it:
+- entry names and hard link targets must be inside of dstDir
+- symlink targets must be relative and resolve inside of dstDir, following the symlinks extracted earlier
+- entries can't be written through a symlink extracted earlier from the same archive
+*/
+type archiveWriter struct {
+	dstDir string
+	// symlinks maps the path of the extracted symlinks to their targets.
+	symlinks map[string]string
+}
+
+func newArchiveWriter(dstDir string) *archiveWriter {
+	return &archiveWriter{dstDir: filepath.Clean(dstDir), symlinks: map[string]string{}}
+}
+
+// entryPath returns the destination path of an archive entry
+// and rejects entries pointing outside of dstDir or into a symlink extracted earlier.
+func (w *archiveWriter) entryPath(name string) (string, error) {
+	pth, err := archiveEntryPath(w.dstDir, name)
+	if err != nil {
+		return "", err
+	}
+
+	for dir := pth; dir != w.dstDir && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if _, ok := w.symlinks[dir]; ok {
+			return "", fmt.Errorf("invalid archive entry: %s (written through a symlink)", name)
+		}
+	}
+	return pth, nil
+}
+
+func (w *archiveWriter) writeSymlink(name, target, pth string) error {
+	if _, ok := w.resolve(filepath.Dir(pth), target, 0); !ok {
+		return fmt.Errorf("invalid archive entry: %s (symlink to %s)", name, target)
+	}
+
+	if err := writeSymlink(target, pth); err != nil {
+		return err
+	}
+	w.symlinks[pth] = target
+	return nil
+}
+
+// resolve returns the path the symlink target resolves to from dir, following the symlinks extracted earlier.
+// Returns false if the target is absolute or leaves dstDir at any point.
+func (w *archiveWriter) resolve(dir, target string, depth int) (string, bool) {
+	if depth > maxSymlinkDepth || target == "" || filepath.IsAbs(target) || strings.HasPrefix(target, "/") {
+		return "", false
+	}
+
+	current := dir
+	for _, component := range strings.Split(filepath.ToSlash(target), "/") {
+		switch component {
+		case "", ".":
+			continue
+		case "..":
+			current = filepath.Dir(current)
+		default:
+			current = filepath.Join(current, component)
+			if linkTarget, ok := w.symlinks[current]; ok {
+				resolved, ok := w.resolve(filepath.Dir(current), linkTarget, depth+1)
+				if !ok {
+					return "", false
+				}
+				current = resolved
+			}
+		}
+		if !isInsideDir(w.dstDir, current) {
+			return "", false
+		}
+	}
+	return current, true
+}
+
+// isInsideDir tells whether the cleaned pth is dir or a path inside of it.
+func isInsideDir(dir, pth string) bool {
+	return pth == dir || strings.HasPrefix(pth, dir+string(os.PathSeparator))
+}
+
+func writeFile(r io.Reader, pth string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(pth, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeSymlink(target, pth string) error {
+	if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(target, pth)
+}
@@ -0,0 +1,289 @@
+package fluttersdk
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz"
+)
+
+func TestInstaller_Install(t *testing.T) {
+	zipArchive := createZipArchive(t, map[string]string{"flutter/bin/flutter": "#!/bin/sh"})
+	tarXZArchive := createTarXZArchive(t, map[string]string{"flutter/bin/flutter": "#!/bin/sh"})
+	zipSlipArchive := createZipArchive(t, map[string]string{"../evil": "evil"})
+
+	tests := []struct {
+		name     string
+		archive  string
+		content  []byte
+		sha256   string
+		wantFile string
+		wantErr  string
+	}{
+		{
+			name:     "Installs zip archive",
+			archive:  "stable/macos/flutter_macos_3.13.9-stable.zip",
+			content:  zipArchive,
+			sha256:   sha256Hex(zipArchive),
+			wantFile: "flutter/bin/flutter",
+		},
+		{
+			name:     "Installs tar.xz archive",
+			archive:  "stable/linux/flutter_linux_3.13.9-stable.tar.xz",
+			content:  tarXZArchive,
+			sha256:   sha256Hex(tarXZArchive),
+			wantFile: "flutter/bin/flutter",
+		},
+		{
+			name:    "Checksum mismatch",
+			archive: "stable/macos/flutter_macos_3.13.9-stable.zip",
+			content: zipArchive,
+			sha256:  sha256Hex([]byte("other")),
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:    "Archive entry outside of the target directory",
+			archive: "stable/macos/flutter_macos_3.13.9-stable.zip",
+			content: zipSlipArchive,
+			sha256:  sha256Hex(zipSlipArchive),
+			wantErr: "invalid archive entry: ../evil",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/releases/"+tt.archive, r.URL.Path)
+				_, err := w.Write(tt.content)
+				require.NoError(t, err)
+			}))
+			defer ts.Close()
+
+			release := Release{
+				Version: "3.13.9",
+				Channel: "stable",
+				Archive: tt.archive,
+				Sha256:  tt.sha256,
+				BaseURL: ts.URL + "/releases",
+			}
+			targetDir := filepath.Join(t.TempDir(), "sdk")
+
+			err := NewInstaller().Install(release, targetDir)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				require.NoDirExists(t, targetDir)
+				entries, err := os.ReadDir(filepath.Dir(targetDir))
+				require.NoError(t, err)
+				require.Empty(t, entries)
+			} else {
+				require.NoError(t, err)
+				require.FileExists(t, filepath.Join(targetDir, tt.wantFile))
+			}
+		})
+	}
+}
+
+func TestInstaller_Install_Symlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		wantErr string
+	}{
+		{
+			name: "Relative symlink inside of the target directory",
+			entries: []archiveEntry{
+				{name: "flutter/bin/cache/dart-sdk/bin/dart", content: "#!/bin/sh"},
+				{name: "flutter/bin/dart", symlink: "cache/dart-sdk/bin/dart"},
+			},
+		},
+		{
+			name: "Absolute symlink",
+			entries: []archiveEntry{
+				{name: "flutter/lib", symlink: "/etc"},
+				{name: "flutter/lib/x", content: "evil"},
+			},
+			wantErr: "invalid archive entry: flutter/lib (symlink to /etc)",
+		},
+		{
+			name: "Symlink outside of the target directory",
+			entries: []archiveEntry{
+				{name: "flutter/lib", symlink: "../../outside"},
+			},
+			wantErr: "invalid archive entry: flutter/lib (symlink to ../../outside)",
+		},
+		{
+			name: "Symlink escaping through an earlier symlink",
+			entries: []archiveEntry{
+				{name: "root", symlink: "."},
+				{name: "lib", symlink: "root/../outside"},
+			},
+			wantErr: "invalid archive entry: lib (symlink to root/../outside)",
+		},
+		{
+			name: "File written through an earlier symlink",
+			entries: []archiveEntry{
+				{name: "flutter/packages/flutter", content: "package"},
+				{name: "flutter/lib", symlink: "packages"},
+				{name: "flutter/lib/x", content: "evil"},
+			},
+			wantErr: "invalid archive entry: flutter/lib/x (written through a symlink)",
+		},
+	}
+	for _, tt := range tests {
+		for _, archive := range []string{"stable/macos/flutter_macos_3.13.9-stable.zip", "stable/linux/flutter_linux_3.13.9-stable.tar.xz"} {
+			t.Run(tt.name+" "+filepath.Ext(archive), func(t *testing.T) {
+				content := createTarXZArchiveEntries(t, tt.entries)
+				if filepath.Ext(archive) == ".zip" {
+					content = createZipArchiveEntries(t, tt.entries)
+				}
+				ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, err := w.Write(content)
+					require.NoError(t, err)
+				}))
+				defer ts.Close()
+
+				release := Release{Version: "3.13.9", Channel: "stable", Archive: archive, Sha256: sha256Hex(content), BaseURL: ts.URL}
+				targetDir := filepath.Join(t.TempDir(), "sdk")
+
+				err := NewInstaller().Install(release, targetDir)
+				if tt.wantErr != "" {
+					require.ErrorContains(t, err, tt.wantErr)
+					require.NoDirExists(t, targetDir)
+					return
+				}
+				require.NoError(t, err)
+				for _, entry := range tt.entries {
+					if entry.symlink != "" {
+						target, err := os.Readlink(filepath.Join(targetDir, entry.name))
+						require.NoError(t, err)
+						require.Equal(t, entry.symlink, target)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestInstaller_Install_TargetExists(t *testing.T) {
+	targetDir := t.TempDir()
+	err := NewInstaller().Install(Release{BaseURL: "https://example.com", Archive: "flutter.zip", Sha256: "abc"}, targetDir)
+	require.EqualError(t, err, "target directory already exists: "+targetDir)
+}
+
+func TestInstaller_InstallContext(t *testing.T) {
+	zipArchive := createZipArchive(t, map[string]string{"flutter/bin/flutter": "#!/bin/sh"})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write(zipArchive)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	release := Release{Version: "3.13.9", Channel: "stable", Archive: "flutter.zip", Sha256: sha256Hex(zipArchive), BaseURL: ts.URL}
+
+	t.Run("Custom HTTP client", func(t *testing.T) {
+		transport := &countingRoundTripper{roundTripper: http.DefaultTransport}
+		targetDir := filepath.Join(t.TempDir(), "sdk")
+
+		err := NewInstaller(WithInstallerHTTPClient(&http.Client{Transport: transport})).InstallContext(context.Background(), release, targetDir)
+		require.NoError(t, err)
+		require.Equal(t, 1, transport.requests)
+		require.FileExists(t, filepath.Join(targetDir, "flutter/bin/flutter"))
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		targetDir := filepath.Join(t.TempDir(), "sdk")
+
+		err := NewInstaller(WithArchiveStorageBaseURLs(ts.URL, ts.URL)).InstallContext(ctx, release, targetDir)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NoDirExists(t, targetDir)
+	})
+}
+
+type countingRoundTripper struct {
+	roundTripper http.RoundTripper
+	requests     int
+}
+
+func (t *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return t.roundTripper.RoundTrip(req)
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func createZipArchive(t *testing.T, files map[string]string) []byte {
+	return createZipArchiveEntries(t, fileEntries(files))
+}
+
+func createTarXZArchive(t *testing.T, files map[string]string) []byte {
+	return createTarXZArchiveEntries(t, fileEntries(files))
+}
+
+func fileEntries(files map[string]string) []archiveEntry {
+	var entries []archiveEntry
+	for name, content := range files {
+		entries = append(entries, archiveEntry{name: name, content: content})
+	}
+	return entries
+}
+
+// archiveEntry is a file (content) or a symlink (symlink target) of a test archive.
+type archiveEntry struct {
+	name    string
+	content string
+	symlink string
+}
+
+func createZipArchiveEntries(t *testing.T, entries []archiveEntry) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		content := entry.content
+		if entry.symlink != "" {
+			header.SetMode(os.ModeSymlink | 0777)
+			content = entry.symlink
+		} else {
+			header.SetMode(0755)
+		}
+		f, err := w.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func createTarXZArchiveEntries(t *testing.T, entries []archiveEntry) []byte {
+	var buf bytes.Buffer
+	xzWriter, err := xz.NewWriter(&buf)
+	require.NoError(t, err)
+	w := tar.NewWriter(xzWriter)
+	for _, entry := range entries {
+		if entry.symlink != "" {
+			require.NoError(t, w.WriteHeader(&tar.Header{Name: entry.name, Mode: 0777, Linkname: entry.symlink, Typeflag: tar.TypeSymlink}))
+			continue
+		}
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: entry.name, Mode: 0755, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}))
+		_, err := w.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, xzWriter.Close())
+	return buf.Bytes()
+}
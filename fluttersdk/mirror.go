@@ -0,0 +1,58 @@
+package fluttersdk
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// StorageBaseURLEnvKey is the environment variable the Flutter tool reads the storage mirror from.
+	StorageBaseURLEnvKey = "FLUTTER_STORAGE_BASE_URL"
+	// DefaultStorageBaseURL is the official storage of the Flutter SDK releases.
+	DefaultStorageBaseURL = "https://storage.googleapis.com"
+
+	flutterInfraReleasesPath     = "/flutter_infra_release/releases"
+	releasesManifestFileNameFmt  = "releases_%s.json"
+	flutterInfraReleasesPathRoot = "/flutter_infra_release/"
+)
+
+// WithStorageBaseURLs sets the storages (the official one or mirrors) the releases manifest is downloaded from.
+// The storages are tried in order, the release archives will point to the storage the manifest was downloaded from.
+// Defaults to FLUTTER_STORAGE_BASE_URL if set, otherwise to the official storage.
+func WithStorageBaseURLs(storageBaseURLs ...string) SDKVersionListerOption {
+	return func(l *defaultSDKVersionLister) {
+		l.storageBaseURLs = storageBaseURLs
+	}
+}
+
+type InstallerOption func(*Installer)
+
+// WithArchiveStorageBaseURLs sets the storages (the official one or mirrors) the release archives are downloaded from.
+// The storages are tried in order. By default, archives are downloaded from the release's BaseURL.
+func WithArchiveStorageBaseURLs(storageBaseURLs ...string) InstallerOption {
+	return func(i *Installer) {
+		i.storageBaseURLs = storageBaseURLs
+	}
+}
+
+func defaultStorageBaseURLs() []string {
+	if storageBaseURL := os.Getenv(StorageBaseURLEnvKey); storageBaseURL != "" {
+		return []string{storageBaseURL}
+	}
+	return []string{DefaultStorageBaseURL}
+}
+
+func releasesManifestURL(storageBaseURL string, platform Platform) string {
+	return strings.TrimSuffix(storageBaseURL, "/") + flutterInfraReleasesPath + "/" + fmt.Sprintf(releasesManifestFileNameFmt, platform)
+}
+
+// rewriteStorageBaseURL points a flutter_infra_release URL (like the releases manifest's base_url) to the given storage.
+// URLs which are not under flutter_infra_release are returned as is.
+func rewriteStorageBaseURL(url, storageBaseURL string) string {
+	idx := strings.Index(url, flutterInfraReleasesPathRoot)
+	if idx == -1 {
+		return url
+	}
+	return strings.TrimSuffix(storageBaseURL, "/") + url[idx:]
+}
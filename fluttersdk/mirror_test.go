@@ -0,0 +1,100 @@
+package fluttersdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rewriteStorageBaseURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		storageBaseURL string
+		want           string
+	}{
+		{
+			name:           "Official base url",
+			url:            "https://storage.googleapis.com/flutter_infra_release/releases",
+			storageBaseURL: "https://storage.flutter-io.cn",
+			want:           "https://storage.flutter-io.cn/flutter_infra_release/releases",
+		},
+		{
+			name:           "Mirror with trailing slash and path prefix",
+			url:            "https://storage.googleapis.com/flutter_infra_release/releases/stable/linux/flutter_linux_3.13.9-stable.tar.xz",
+			storageBaseURL: "https://artifacts.example.com/flutter/",
+			want:           "https://artifacts.example.com/flutter/flutter_infra_release/releases/stable/linux/flutter_linux_3.13.9-stable.tar.xz",
+		},
+		{
+			name:           "Unknown url layout",
+			url:            "https://example.com/releases",
+			storageBaseURL: "https://storage.flutter-io.cn",
+			want:           "https://example.com/releases",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, rewriteStorageBaseURL(tt.url, tt.storageBaseURL))
+		})
+	}
+}
+
+func TestSDKVersionLister_Mirrors(t *testing.T) {
+	failingMirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failingMirror.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/flutter_infra_release/releases/releases_macos.json", r.URL.Path)
+		_, err := w.Write([]byte(flutterSDKsResponse))
+		require.NoError(t, err)
+	}))
+	defer mirror.Close()
+
+	lister := NewSDKVersionLister(WithStorageBaseURLs(failingMirror.URL, mirror.URL))
+	releases, err := lister.ListReleasesByChannel(context.Background(), MacOS, ARM64)
+	require.NoError(t, err)
+	require.Equal(t, mirror.URL+"/flutter_infra_release/releases", releases["stable"][0].BaseURL)
+}
+
+func TestSDKVersionLister_StorageBaseURLFromEnv(t *testing.T) {
+	t.Setenv(StorageBaseURLEnvKey, "https://storage.flutter-io.cn")
+	require.Equal(t, []string{"https://storage.flutter-io.cn"}, newDefaultSDKVersionLister().storageBaseURLs)
+
+	t.Setenv(StorageBaseURLEnvKey, "")
+	require.Equal(t, []string{DefaultStorageBaseURL}, newDefaultSDKVersionLister().storageBaseURLs)
+}
+
+func TestInstaller_Mirrors(t *testing.T) {
+	archive := createZipArchive(t, map[string]string{"flutter/bin/flutter": "#!/bin/sh"})
+
+	failingMirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer failingMirror.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/flutter_infra_release/releases/stable/macos/flutter_macos_3.13.9-stable.zip", r.URL.Path)
+		_, err := w.Write(archive)
+		require.NoError(t, err)
+	}))
+	defer mirror.Close()
+
+	release := Release{
+		Version: "3.13.9",
+		Channel: "stable",
+		Archive: "stable/macos/flutter_macos_3.13.9-stable.zip",
+		Sha256:  sha256Hex(archive),
+		BaseURL: "https://storage.googleapis.com/flutter_infra_release/releases",
+	}
+	targetDir := filepath.Join(t.TempDir(), "sdk")
+
+	err := NewInstaller(WithArchiveStorageBaseURLs(failingMirror.URL, mirror.URL)).Install(release, targetDir)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(targetDir, "flutter/bin/flutter"))
+}
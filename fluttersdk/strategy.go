@@ -0,0 +1,80 @@
+package fluttersdk
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ResolutionStrategy decides which of the releases matching an SDKQuery is picked.
+type ResolutionStrategy string
+
+const (
+	// Newest picks the newest matching release, an empty strategy works the same way.
+	Newest ResolutionStrategy = "newest"
+	// OldestCompatible picks the oldest matching release.
+	OldestCompatible ResolutionStrategy = "oldest-compatible"
+	// NewestPatchOfLowestMinor picks the newest patch release of the lowest matching minor version,
+	// for example 3.10.6 for >=3.10.0, so the selection doesn't move to a new minor (or major) version once it's released.
+	NewestPatchOfLowestMinor ResolutionStrategy = "newest-patch-of-lowest-minor"
+	// PreferInstalled picks the newest matching release, which is already installed (see SDKQuery.InstalledFlutterVersions),
+	// and falls back to the newest matching release.
+	PreferInstalled ResolutionStrategy = "prefer-installed"
+)
+
+// ParseResolutionStrategy parses a strategy name, an empty name means the default (Newest) strategy.
+func ParseResolutionStrategy(name string) (ResolutionStrategy, error) {
+	switch strategy := ResolutionStrategy(name); strategy {
+	case "":
+		return Newest, nil
+	case Newest, OldestCompatible, NewestPatchOfLowestMinor, PreferInstalled:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown resolution strategy: %s", name)
+	}
+}
+
+// Validate returns an error for unknown strategies, so a typo doesn't silently fall back to the Newest strategy.
+func (s ResolutionStrategy) Validate() error {
+	_, err := ParseResolutionStrategy(string(s))
+	return err
+}
+
+// pick returns the release the strategy selects from the matching releases, which are in descending version order.
+func (s ResolutionStrategy) pick(releases []CatalogRelease, installedVersions []*semver.Version) *CatalogRelease {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	picked := releases[0]
+	switch s {
+	case OldestCompatible:
+		picked = releases[len(releases)-1]
+	case NewestPatchOfLowestMinor:
+		lowest := releases[len(releases)-1].FlutterVersion
+		for _, release := range releases {
+			if release.FlutterVersion.Major() == lowest.Major() && release.FlutterVersion.Minor() == lowest.Minor() {
+				picked = release
+				break
+			}
+		}
+	case PreferInstalled:
+		for _, release := range releases {
+			if containsVersion(installedVersions, release.FlutterVersion) {
+				picked = release
+				break
+			}
+		}
+	}
+
+	return &picked
+}
+
+func containsVersion(versions []*semver.Version, version *semver.Version) bool {
+	for _, v := range versions {
+		if v != nil && v.Equal(version) {
+			return true
+		}
+	}
+	return false
+}
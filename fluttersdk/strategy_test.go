@@ -0,0 +1,125 @@
+package fluttersdk
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDKVersionFinder_FindLatestReleaseFor_Strategies(t *testing.T) {
+	lister := staticSDKVersionLister{
+		"stable": {
+			{Channel: "stable", Version: "3.22.3", DartSdkVersion: "3.4.4"},
+			{Channel: "stable", Version: "3.22.2", DartSdkVersion: "3.4.3"},
+			{Channel: "stable", Version: "3.19.6", DartSdkVersion: "3.3.4"},
+			{Channel: "stable", Version: "3.10.6", DartSdkVersion: "3.0.6"},
+			{Channel: "stable", Version: "3.10.5", DartSdkVersion: "3.0.5"},
+			{Channel: "stable", Version: "3.7.12", DartSdkVersion: "2.19.6"},
+		},
+		"beta": {
+			{Channel: "beta", Version: "3.23.0-0.1.pre", DartSdkVersion: "3.5.0 (build 3.5.0-180.3.beta)"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		channel     Channel
+		query       SDKQuery
+		wantVersion string
+	}{
+		{
+			name:        "Defaults to the newest release",
+			query:       SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.10.0")},
+			wantVersion: "3.22.3",
+		},
+		{
+			name:        "Newest",
+			query:       SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.10.0"), Strategy: Newest},
+			wantVersion: "3.22.3",
+		},
+		{
+			name:        "Oldest compatible",
+			query:       SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.10.0"), Strategy: OldestCompatible},
+			wantVersion: "3.10.5",
+		},
+		{
+			name:        "Oldest compatible with a Dart constraint",
+			query:       SDKQuery{DartVersionConstraint: mustNewConstraint(t, ">=3.3.0"), Strategy: OldestCompatible},
+			wantVersion: "3.19.6",
+		},
+		{
+			name:        "Newest patch of the lowest minor",
+			query:       SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.10.0"), Strategy: NewestPatchOfLowestMinor},
+			wantVersion: "3.10.6",
+		},
+		{
+			name:        "Prefer installed",
+			query:       SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.10.0"), Strategy: PreferInstalled, InstalledFlutterVersions: []*semver.Version{semver.MustParse("3.7.12"), semver.MustParse("3.19.6")}},
+			wantVersion: "3.19.6",
+		},
+		{
+			name:        "Prefer installed without a matching installed version",
+			query:       SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.10.0"), Strategy: PreferInstalled, InstalledFlutterVersions: []*semver.Version{semver.MustParse("3.7.12")}},
+			wantVersion: "3.22.3",
+		},
+		{
+			name:        "Strategy is applied on the first channel with a matching release",
+			query:       SDKQuery{FlutterVersionConstraint: mustNewConstraint(t, ">=3.23.0-0"), Strategy: OldestCompatible},
+			wantVersion: "3.23.0-0.1.pre",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := SDKVersionFinder{SDKVersionLister: lister}
+			got, err := f.FindLatestReleaseFor(MacOS, ARM64, tt.channel, tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantVersion, got.Version)
+		})
+	}
+}
+
+func TestSDKVersionFinder_FindLatestReleaseFor_UnknownStrategy(t *testing.T) {
+	lister := &countingSDKVersionLister{releasesByChannel: map[string][]Release{"stable": {
+		{Channel: "stable", Version: "3.13.9", DartSdkVersion: "3.1.5"},
+	}}}
+	f := SDKVersionFinder{SDKVersionLister: lister}
+	_, err := f.FindLatestReleaseFor(MacOS, ARM64, Stable, SDKQuery{Strategy: "Newest"})
+	require.EqualError(t, err, "unknown resolution strategy: Newest")
+	require.Equal(t, 0, lister.count)
+}
+
+func TestParseResolutionStrategy(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategyName string
+		want         ResolutionStrategy
+		wantErr      string
+	}{
+		{
+			name: "Empty name",
+			want: Newest,
+		},
+		{
+			name:         "Known strategy",
+			strategyName: "newest-patch-of-lowest-minor",
+			want:         NewestPatchOfLowestMinor,
+		},
+		{
+			name:         "Unknown strategy",
+			strategyName: "random",
+			wantErr:      "unknown resolution strategy: random",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseResolutionStrategy(tt.strategyName)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
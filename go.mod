@@ -7,6 +7,7 @@ require (
 	github.com/bitrise-io/go-utils/v2 v2.0.0-alpha.18
 	github.com/ryanuber/go-glob v1.0.0
 	github.com/stretchr/testify v1.8.3
+	github.com/ulikunitz/xz v0.5.15
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	fluttersdk "github.com/bitrise-io/go-flutter/fluttersdk"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,25 +14,25 @@ type SDKVersionLister struct {
 	mock.Mock
 }
 
-// ListReleasesByChannel provides a mock function with given fields: platform, architecture
-func (_m *SDKVersionLister) ListReleasesByChannel(platform fluttersdk.Platform, architecture fluttersdk.Architecture) (map[string][]fluttersdk.Release, error) {
-	ret := _m.Called(platform, architecture)
+// ListReleasesByChannel provides a mock function with given fields: ctx, platform, architecture
+func (_m *SDKVersionLister) ListReleasesByChannel(ctx context.Context, platform fluttersdk.Platform, architecture fluttersdk.Architecture) (map[string][]fluttersdk.Release, error) {
+	ret := _m.Called(ctx, platform, architecture)
 
 	var r0 map[string][]fluttersdk.Release
 	var r1 error
-	if rf, ok := ret.Get(0).(func(fluttersdk.Platform, fluttersdk.Architecture) (map[string][]fluttersdk.Release, error)); ok {
-		return rf(platform, architecture)
+	if rf, ok := ret.Get(0).(func(context.Context, fluttersdk.Platform, fluttersdk.Architecture) (map[string][]fluttersdk.Release, error)); ok {
+		return rf(ctx, platform, architecture)
 	}
-	if rf, ok := ret.Get(0).(func(fluttersdk.Platform, fluttersdk.Architecture) map[string][]fluttersdk.Release); ok {
-		r0 = rf(platform, architecture)
+	if rf, ok := ret.Get(0).(func(context.Context, fluttersdk.Platform, fluttersdk.Architecture) map[string][]fluttersdk.Release); ok {
+		r0 = rf(ctx, platform, architecture)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(map[string][]fluttersdk.Release)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(fluttersdk.Platform, fluttersdk.Architecture) error); ok {
-		r1 = rf(platform, architecture)
+	if rf, ok := ret.Get(1).(func(context.Context, fluttersdk.Platform, fluttersdk.Architecture) error); ok {
+		r1 = rf(ctx, platform, architecture)
 	} else {
 		r1 = ret.Error(1)
 	}